@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/repositories"
+	"gofiber-smart-trash/domain/services"
+
+	"gorm.io/gorm"
+)
+
+type binMappingServiceImpl struct {
+	binRuleRepo repositories.BinRuleRepository
+	active      atomic.Pointer[models.BinRuleSet] // nil while no rule set is active
+}
+
+// NewBinMappingService creates a new instance of BinMappingService. No rules
+// are applied until Reload has read the active rule set.
+func NewBinMappingService(binRuleRepo repositories.BinRuleRepository) services.BinMappingService {
+	return &binMappingServiceImpl{
+		binRuleRepo: binRuleRepo,
+	}
+}
+
+// Assign evaluates the cached active rule set; it never touches the database
+func (s *binMappingServiceImpl) Assign(category, subCategory, l0Label string, confidence float64) (int, string, int) {
+	ruleSet := s.active.Load()
+	if ruleSet == nil {
+		return 0, "", 0
+	}
+	for i := range ruleSet.Rules {
+		rule := &ruleSet.Rules[i]
+		if rule.Matches(category, subCategory, l0Label, confidence) {
+			return rule.BinNumber, rule.BinLabel, ruleSet.Version
+		}
+	}
+	return 0, "", 0
+}
+
+// Reload replaces the cached rule set with the active one in the database
+func (s *binMappingServiceImpl) Reload(ctx context.Context) error {
+	ruleSet, err := s.binRuleRepo.FindActive(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		ruleSet, err = nil, nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load bin rules: %w", err)
+	}
+
+	previous := s.active.Swap(ruleSet)
+	switch {
+	case ruleSet == nil && previous != nil:
+		log.Printf("[Bins] No rule set active, using the AI service's bins")
+	case ruleSet != nil && (previous == nil || previous.Version != ruleSet.Version):
+		log.Printf("[Bins] Using rule set v%d (%d rules)", ruleSet.Version, len(ruleSet.Rules))
+	}
+	return nil
+}
+
+// ActiveRuleSet returns the rule set currently stored as active
+func (s *binMappingServiceImpl) ActiveRuleSet(ctx context.Context) (*dto.BinRuleSetResponse, error) {
+	ruleSet, err := s.binRuleRepo.FindActive(ctx)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, services.ErrBinRuleSetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get active bin rules: %w", err)
+	}
+	return toBinRuleSetResponse(ruleSet), nil
+}
+
+// ListRuleSets returns every version, newest first, without their rules
+func (s *binMappingServiceImpl) ListRuleSets(ctx context.Context) ([]dto.BinRuleSetResponse, error) {
+	ruleSets, err := s.binRuleRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bin rules: %w", err)
+	}
+	responses := make([]dto.BinRuleSetResponse, len(ruleSets))
+	for i := range ruleSets {
+		responses[i] = *toBinRuleSetResponse(&ruleSets[i])
+	}
+	return responses, nil
+}
+
+// GetRuleSet returns one version with its rules
+func (s *binMappingServiceImpl) GetRuleSet(ctx context.Context, version int) (*dto.BinRuleSetResponse, error) {
+	ruleSet, err := s.binRuleRepo.FindByVersion(ctx, version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, services.ErrBinRuleSetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bin rules v%d: %w", version, err)
+	}
+	return toBinRuleSetResponse(ruleSet), nil
+}
+
+// CreateRuleSet saves the rules as a new version, active unless req.Activate is false
+func (s *binMappingServiceImpl) CreateRuleSet(ctx context.Context, req *dto.CreateBinRuleSetRequest) (*dto.BinRuleSetResponse, error) {
+	ruleSet := &models.BinRuleSet{
+		Note:      req.Note,
+		CreatedBy: req.CreatedBy,
+		Rules:     make([]models.BinRule, len(req.Rules)),
+	}
+	for i, rule := range req.Rules {
+		ruleSet.Rules[i] = models.BinRule{
+			Position:      i + 1,
+			Category:      strings.TrimSpace(rule.Category),
+			SubCategory:   strings.TrimSpace(rule.SubCategory),
+			L0Label:       strings.TrimSpace(rule.L0Label),
+			MinConfidence: rule.MinConfidence,
+			BinNumber:     rule.BinNumber,
+			BinLabel:      strings.TrimSpace(rule.BinLabel),
+		}
+	}
+	activate := req.Activate == nil || *req.Activate
+
+	if err := s.binRuleRepo.Create(ctx, ruleSet, activate); err != nil {
+		return nil, fmt.Errorf("failed to save bin rules: %w", err)
+	}
+	log.Printf("[Bins] Rule set v%d with %d rules created by %s (active: %v)", ruleSet.Version, len(ruleSet.Rules), ruleSet.CreatedBy, activate)
+
+	if activate {
+		if err := s.Reload(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return toBinRuleSetResponse(ruleSet), nil
+}
+
+// ActivateRuleSet makes a version active, e.g. to roll back a bad change
+func (s *binMappingServiceImpl) ActivateRuleSet(ctx context.Context, version int) (*dto.BinRuleSetResponse, error) {
+	err := s.binRuleRepo.Activate(ctx, version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, services.ErrBinRuleSetNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to activate bin rules v%d: %w", version, err)
+	}
+	if err := s.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return s.GetRuleSet(ctx, version)
+}
+
+// toBinRuleSetResponse converts a rule set to its API representation
+func toBinRuleSetResponse(ruleSet *models.BinRuleSet) *dto.BinRuleSetResponse {
+	response := &dto.BinRuleSetResponse{
+		Version:     ruleSet.Version,
+		Active:      ruleSet.Active,
+		Note:        ruleSet.Note,
+		CreatedBy:   ruleSet.CreatedBy,
+		CreatedAt:   ruleSet.CreatedAt,
+		ActivatedAt: ruleSet.ActivatedAt,
+	}
+	for _, rule := range ruleSet.Rules {
+		response.Rules = append(response.Rules, dto.BinRuleEntry{
+			Category:      rule.Category,
+			SubCategory:   rule.SubCategory,
+			L0Label:       rule.L0Label,
+			MinConfidence: rule.MinConfidence,
+			BinNumber:     rule.BinNumber,
+			BinLabel:      rule.BinLabel,
+		})
+	}
+	return response
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"slices"
+	"time"
+
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/ports"
+	"gofiber-smart-trash/domain/repositories"
+	"gofiber-smart-trash/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClassificationServiceConfig holds the tunable behaviour of the classification service
+type ClassificationServiceConfig struct {
+	// Private bucket mode: the AI service gets a short-lived signed URL
+	PrivateBucket bool
+	SignedURLTTL  time.Duration
+
+	// Read images from storage and pass their bytes, for AI backends in bytes mode
+	SendImageData bool
+
+	// Job queue (async mode)
+	MaxAttempts  int           // Attempts before a job and its record are marked failed
+	RetryBackoff time.Duration // Delay before the first retry, doubled on each further attempt
+	JobLease     time.Duration // Running jobs not finished within this are picked up again
+
+	// Review queue: results below ReviewConfidenceThreshold (0 disables) or, with
+	// ReviewNoObject, without an L0 detection are flagged for an operator
+	ReviewConfidenceThreshold float64
+	ReviewNoObject            bool
+}
+
+type classificationServiceImpl struct {
+	trashRepo      repositories.TrashRepository
+	jobRepo        repositories.ClassificationJobRepository
+	storageAdapter ports.StorageAdapter
+	aiAdapter      ports.AIAdapter
+	shadow         services.ShadowService     // nil disables shadow classification
+	bins           services.BinMappingService // nil keeps the bins suggested by the AI service
+	config         ClassificationServiceConfig
+}
+
+// NewClassificationService creates a new instance of ClassificationService
+func NewClassificationService(trashRepo repositories.TrashRepository, jobRepo repositories.ClassificationJobRepository, storageAdapter ports.StorageAdapter, aiAdapter ports.AIAdapter, shadow services.ShadowService, bins services.BinMappingService, config ClassificationServiceConfig) services.ClassificationService {
+	if config.SignedURLTTL == 0 {
+		config.SignedURLTTL = 15 * time.Minute
+	}
+	if config.MaxAttempts <= 0 {
+		config.MaxAttempts = 3
+	}
+	if config.RetryBackoff == 0 {
+		config.RetryBackoff = 30 * time.Second
+	}
+	if config.JobLease == 0 {
+		config.JobLease = 5 * time.Minute
+	}
+
+	return &classificationServiceImpl{
+		trashRepo:      trashRepo,
+		jobRepo:        jobRepo,
+		storageAdapter: storageAdapter,
+		aiAdapter:      aiAdapter,
+		shadow:         shadow,
+		bins:           bins,
+		config:         config,
+	}
+}
+
+// Classify classifies a record without saving it. The shadow model only gets
+// the image through SubmitShadow, once the record has been saved.
+func (s *classificationServiceImpl) Classify(ctx context.Context, trash *models.TrashRecord) (*ports.ClassificationResult, error) {
+	result, _, err := s.classify(ctx, trash)
+	return result, err
+}
+
+// SubmitShadow sends a saved record's image and production result to the
+// shadow model, if one is configured. Reclassifications are never submitted,
+// so bulk runs over old records do not skew the report towards them.
+func (s *classificationServiceImpl) SubmitShadow(ctx context.Context, trash *models.TrashRecord, result *ports.ClassificationResult) {
+	if s.shadow == nil || result == nil {
+		return
+	}
+	image, err := s.image(ctx, trash)
+	if err != nil {
+		log.Printf("[Shadow] Skipping record %s: %v", trash.ID, err)
+		return
+	}
+	s.shadow.Submit(trash, image, result)
+}
+
+// classify sends the record's image to the AI service, copies the result onto
+// trash and appends it to trash.Classifications. The bin comes from the active
+// bin rules, with the AI service's suggestion as fallback. The image is
+// returned for the shadow model, which gets it once the record is saved.
+func (s *classificationServiceImpl) classify(ctx context.Context, trash *models.TrashRecord) (*ports.ClassificationResult, ports.ImageData, error) {
+	started := time.Now()
+	image, err := s.image(ctx, trash)
+	var result *ports.ClassificationResult
+	if err == nil {
+		log.Printf("[AI] Classifying image: %s", trash.ImageKey)
+		if image.Data != nil {
+			result, err = s.aiAdapter.ClassifyImageData(ctx, image)
+		} else {
+			result, err = s.aiAdapter.ClassifyImage(ctx, image.URL)
+		}
+	}
+	latency := time.Since(started)
+	if err != nil {
+		log.Printf("[AI] Classification failed: %v", err)
+		trash.ClassificationStatus = models.ClassificationFailed
+		trash.ClassifyError = err.Error()
+		return nil, image, err
+	}
+
+	log.Printf("[AI] L0 (YOLO): detected=%v, label=%s (%.2f%%), objects=%d",
+		result.L0Detected, result.L0Label, result.L0Confidence*100, len(result.Detections))
+	log.Printf("[AI] L1 (Trash-Net): %s (%.2f%%)",
+		result.Category, result.Confidence*100)
+	binNumber, binLabel, binRuleVersion := s.binFor(result.Category, result.SubCategory, result.L0Label, result.Confidence, result.BinNumber, result.BinLabel)
+	if binRuleVersion > 0 && binNumber != result.BinNumber {
+		log.Printf("[AI] Bin %d (%s) from rule set v%d, AI suggested %d", binNumber, binLabel, binRuleVersion, result.BinNumber)
+	}
+	trash.ClassificationStatus = models.ClassificationClassified
+	trash.ClassifyError = ""
+	trash.ClassifiedAt = time.Now()
+	// An operator's label takes precedence over later AI results, which only go to the history
+	if trash.ReviewStatus != models.ReviewReviewed {
+		trash.Category = result.Category
+		trash.SubCategory = result.SubCategory
+		trash.Confidence = result.Confidence
+		trash.BinNumber = binNumber
+		trash.BinLabel = binLabel
+		trash.ReviewStatus, trash.ReviewReason = s.reviewFor(result)
+		if trash.ReviewStatus == models.ReviewPending {
+			log.Printf("[AI] Record %s queued for review: %s", trash.ID, trash.ReviewReason)
+		}
+	}
+	classificationID := uuid.New()
+	// Most confident first, as in the API response and the loaded history
+	slices.SortStableFunc(result.Detections, func(a, b ports.Detection) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+	detections := make([]models.Detection, len(result.Detections))
+	for i, d := range result.Detections {
+		detectionBin, detectionBinLabel := d.BinNumber, d.BinLabel
+		// Objects the AI service did not classify on their own have no bin to map
+		if d.Category != "" {
+			detectionBin, detectionBinLabel, _ = s.binFor(d.Category, d.SubCategory, d.Label, d.CategoryConfidence, d.BinNumber, d.BinLabel)
+		}
+		detections[i] = models.Detection{
+			ID:                 uuid.New(),
+			ClassificationID:   classificationID,
+			TrashRecordID:      trash.ID,
+			Label:              d.Label,
+			Confidence:         d.Confidence,
+			BoxX:               d.BBox.X,
+			BoxY:               d.BBox.Y,
+			BoxWidth:           d.BBox.Width,
+			BoxHeight:          d.BBox.Height,
+			Category:           d.Category,
+			SubCategory:        d.SubCategory,
+			CategoryConfidence: d.CategoryConfidence,
+			BinNumber:          detectionBin,
+			BinLabel:           detectionBinLabel,
+			CreatedAt:          trash.ClassifiedAt,
+		}
+	}
+	trash.Classifications = append(trash.Classifications, models.Classification{
+		ID:             classificationID,
+		TrashRecordID:  trash.ID,
+		Category:       result.Category,
+		SubCategory:    result.SubCategory,
+		Confidence:     result.Confidence,
+		BinNumber:      binNumber,
+		BinLabel:       binLabel,
+		AIBinNumber:    result.BinNumber,
+		AIBinLabel:     result.BinLabel,
+		BinRuleVersion: binRuleVersion,
+		L0Detected:     result.L0Detected,
+		L0Label:        result.L0Label,
+		L0Confidence:   result.L0Confidence,
+		Detections:     detections,
+		ModelName:      result.ModelName,
+		ModelVersion:   result.ModelVersion,
+		Backend:        result.Backend,
+		LatencyMs:      latency.Milliseconds(),
+		CreatedAt:      trash.ClassifiedAt,
+	})
+	return result, image, nil
+}
+
+// Reclassify classifies a saved record again. The new result is applied to
+// trash only once it has been stored.
+func (s *classificationServiceImpl) Reclassify(ctx context.Context, trash *models.TrashRecord) (*ports.ClassificationResult, error) {
+	updated := *trash
+	updated.Classifications = slices.Clone(trash.Classifications)
+	result, _, err := s.classify(ctx, &updated)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", services.ErrClassificationFailed, err)
+	}
+	if err := s.trashRepo.UpdateClassification(ctx, &updated); err != nil {
+		return nil, fmt.Errorf("failed to save classification of %s: %w", trash.ID, err)
+	}
+	*trash = updated
+	return result, nil
+}
+
+// binFor picks the bin of an item from the active bin rules, falling back to
+// the AI service's suggestion. The version is 0 when the suggestion was used.
+func (s *classificationServiceImpl) binFor(category, subCategory, l0Label string, confidence float64, aiBinNumber int, aiBinLabel string) (int, string, int) {
+	if s.bins != nil {
+		if binNumber, binLabel, version := s.bins.Assign(category, subCategory, l0Label, confidence); version > 0 {
+			return binNumber, binLabel, version
+		}
+	}
+	return aiBinNumber, aiBinLabel, 0
+}
+
+// reviewFor decides whether an AI result needs to be checked by an operator
+func (s *classificationServiceImpl) reviewFor(result *ports.ClassificationResult) (models.ReviewStatus, string) {
+	if s.config.ReviewNoObject && !result.L0Detected {
+		return models.ReviewPending, models.ReviewReasonNoObject
+	}
+	if result.Confidence < s.config.ReviewConfidenceThreshold {
+		return models.ReviewPending, models.ReviewReasonLowConfidence
+	}
+	return models.ReviewNone, ""
+}
+
+// image returns what the AI service gets for the record: a URL it can read the
+// image from and, with SendImageData, the image itself
+func (s *classificationServiceImpl) image(ctx context.Context, trash *models.TrashRecord) (ports.ImageData, error) {
+	imageURL, err := s.imageURL(ctx, trash)
+	if err != nil {
+		return ports.ImageData{}, err
+	}
+	image := ports.ImageData{URL: imageURL, ContentType: trash.ImageContentType}
+	if !s.config.SendImageData || trash.ImageKey == "" {
+		return image, nil
+	}
+
+	reader, err := s.storageAdapter.GetObject(ctx, trash.ImageKey)
+	if err != nil {
+		return image, fmt.Errorf("failed to read image: %w", err)
+	}
+	defer reader.Close()
+	if image.Data, err = io.ReadAll(reader); err != nil {
+		return image, fmt.Errorf("failed to read image: %w", err)
+	}
+	return image, nil
+}
+
+// imageURL returns a URL the AI service can read the record's image from
+func (s *classificationServiceImpl) imageURL(ctx context.Context, trash *models.TrashRecord) (string, error) {
+	// Legacy records without a key only have their public URL
+	imageURL := trash.ImageURL
+	if trash.ImageKey != "" {
+		imageURL = s.storageAdapter.GeneratePublicURL(trash.ImageKey)
+		if s.config.PrivateBucket {
+			signedURL, err := s.storageAdapter.GeneratePresignedDownloadURL(ctx, trash.ImageKey, s.config.SignedURLTTL)
+			if err != nil {
+				return "", fmt.Errorf("failed to sign image URL: %w", err)
+			}
+			imageURL = signedURL
+		}
+	}
+	return imageURL, nil
+}
+
+// ProcessNextJob classifies the record of the next due job. Failed attempts are
+// retried with exponential backoff; after the last one the record is marked failed.
+func (s *classificationServiceImpl) ProcessNextJob(ctx context.Context) (bool, error) {
+	job, err := s.jobRepo.ClaimNext(ctx, time.Now().Add(-s.config.JobLease))
+	if err != nil {
+		return false, fmt.Errorf("failed to claim classification job: %w", err)
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	trash, err := s.trashRepo.FindByID(ctx, job.TrashRecordID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Record was deleted while queued, nothing left to classify
+		return true, s.jobRepo.Fail(ctx, job.ID, "trash record not found")
+	}
+	if err != nil {
+		return true, fmt.Errorf("failed to get trash record %s: %w", job.TrashRecordID, err)
+	}
+
+	result, image, classifyErr := s.classify(ctx, trash)
+	// Shutting down: leave the job running so it is reclaimed after its lease
+	if ctx.Err() != nil {
+		return true, nil
+	}
+
+	if classifyErr != nil && job.Attempts < s.config.MaxAttempts {
+		runAt := time.Now().Add(s.config.RetryBackoff << (job.Attempts - 1))
+		log.Printf("[AI] Job %s attempt %d/%d failed, retrying at %s", job.ID, job.Attempts, s.config.MaxAttempts, runAt.Format(time.RFC3339))
+		return true, s.jobRepo.Retry(ctx, job.ID, runAt, classifyErr.Error())
+	}
+
+	if err := s.trashRepo.UpdateClassification(ctx, trash); err != nil {
+		return true, fmt.Errorf("failed to save classification of %s: %w", trash.ID, err)
+	}
+	if classifyErr != nil {
+		return true, s.jobRepo.Fail(ctx, job.ID, classifyErr.Error())
+	}
+	// The image was already read for the primary model, so it is reused here
+	if s.shadow != nil {
+		s.shadow.Submit(trash, image, result)
+	}
+	return true, s.jobRepo.Complete(ctx, job.ID)
+}
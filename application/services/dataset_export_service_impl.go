@@ -0,0 +1,462 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"slices"
+	"strconv"
+	"time"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/ports"
+	"gofiber-smart-trash/domain/repositories"
+	"gofiber-smart-trash/domain/services"
+	"gofiber-smart-trash/pkg/dataset"
+	"gofiber-smart-trash/pkg/imaging"
+
+	"github.com/google/uuid"
+)
+
+const (
+	datasetDefaultBatchSize  = 100
+	datasetDefaultTrainRatio = 0.8
+	datasetDefaultValRatio   = 0.1
+	datasetDefaultSeed       = "smart-trash"
+)
+
+var datasetFormats = []string{dto.DatasetFormatImageFolder, dto.DatasetFormatYOLO, dto.DatasetFormatCOCO}
+
+// Detection formats are opt-in: only records classified since bounding boxes
+// are stored can be labelled, so they would mostly be skipped
+var datasetDefaultFormats = []string{dto.DatasetFormatImageFolder}
+
+var datasetReviewStatuses = map[string]models.ReviewStatus{
+	"reviewed": models.ReviewReviewed,
+	"pending":  models.ReviewPending,
+	"none":     models.ReviewNone,
+}
+
+// datasetDetection is one L0 object of a record
+type datasetDetection struct {
+	label      string
+	confidence float64
+	box        *dataset.Box // In pixels; nil when no bounding box was stored
+}
+
+// datasetDetectionImage is an image of the detection dataset, kept until the
+// class list is complete and the label files can be written
+type datasetDetectionImage struct {
+	id         string
+	split      string
+	fileName   string
+	width      int
+	height     int
+	detections []datasetDetection
+}
+
+type datasetExportServiceImpl struct {
+	trashRepo      repositories.TrashRepository
+	storageAdapter ports.StorageAdapter
+}
+
+// NewDatasetExportService creates a new instance of DatasetExportService
+func NewDatasetExportService(trashRepo repositories.TrashRepository, storageAdapter ports.StorageAdapter) services.DatasetExportService {
+	return &datasetExportServiceImpl{
+		trashRepo:      trashRepo,
+		storageAdapter: storageAdapter,
+	}
+}
+
+// Validate fills in default formats, ratios and seed and checks the options
+func (s *datasetExportServiceImpl) Validate(opts *dto.DatasetExportOptions) error {
+	if len(opts.Formats) == 0 {
+		opts.Formats = slices.Clone(datasetDefaultFormats)
+	}
+	for _, format := range opts.Formats {
+		if !slices.Contains(datasetFormats, format) {
+			return fmt.Errorf("%w: unknown format '%s'", services.ErrInvalidExport, format)
+		}
+	}
+	for _, status := range opts.ReviewStatuses {
+		if _, ok := datasetReviewStatuses[status]; !ok {
+			return fmt.Errorf("%w: unknown review status '%s'", services.ErrInvalidExport, status)
+		}
+	}
+	if opts.TrainRatio == 0 && opts.ValRatio == 0 {
+		opts.TrainRatio = datasetDefaultTrainRatio
+		opts.ValRatio = datasetDefaultValRatio
+	}
+	if err := (dataset.Ratios{Train: opts.TrainRatio, Val: opts.ValRatio}).Validate(); err != nil {
+		return fmt.Errorf("%w: %v", services.ErrInvalidExport, err)
+	}
+	if opts.MinConfidence < 0 || opts.MinConfidence > 1 {
+		return fmt.Errorf("%w: min confidence must be between 0 and 1", services.ErrInvalidExport)
+	}
+	if opts.Seed == "" {
+		opts.Seed = datasetDefaultSeed
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = datasetDefaultBatchSize
+	}
+	return nil
+}
+
+// Export streams matching records into a zip archive:
+//
+//	classification/<split>/<category>/<id>.<ext>       ImageFolder for the L1 classifier
+//	detection/images/<split>/<id>.<ext>                 L0 detector images
+//	detection/labels/<split>/<id>.txt, data.yaml        YOLO labels
+//	detection/annotations/instances_<split>.json        COCO annotations
+//	labels.csv, manifest.json
+//
+// Records sharing an image (duplicate submissions) are exported once. Only
+// records with stored bounding boxes go into the detection dataset.
+func (s *datasetExportServiceImpl) Export(ctx context.Context, opts dto.DatasetExportOptions, w io.Writer) (*dto.DatasetExportReport, error) {
+	if err := s.Validate(&opts); err != nil {
+		return nil, err
+	}
+
+	report := &dto.DatasetExportReport{
+		Formats:    opts.Formats,
+		Seed:       opts.Seed,
+		TrainRatio: opts.TrainRatio,
+		ValRatio:   opts.ValRatio,
+		Splits:     map[string]int{},
+		Classes:    map[string]int{},
+		Failures:   []dto.DatasetExportFailure{},
+		StartedAt:  time.Now(),
+	}
+
+	filter := repositories.DatasetFilter{
+		CreatedFrom:   opts.CreatedFrom,
+		CreatedTo:     opts.CreatedTo,
+		MinConfidence: opts.MinConfidence,
+	}
+	for _, status := range opts.ReviewStatuses {
+		filter.ReviewStatuses = append(filter.ReviewStatuses, datasetReviewStatuses[status])
+	}
+
+	imageFolder := slices.Contains(opts.Formats, dto.DatasetFormatImageFolder)
+	detection := slices.Contains(opts.Formats, dto.DatasetFormatYOLO) || slices.Contains(opts.Formats, dto.DatasetFormatCOCO)
+	ratios := dataset.Ratios{Train: opts.TrainRatio, Val: opts.ValRatio}
+
+	archive := zip.NewWriter(w)
+	var labels bytes.Buffer
+	labelsCSV := csv.NewWriter(&labels)
+	labelsCSV.Write([]string{"record_id", "split", "category", "sub_category", "bin_number", "label_source", "confidence", "model_version", "image"})
+
+	seen := make(map[string]bool)
+	var detectionImages []datasetDetectionImage
+
+	for afterID := uuid.Nil; ; {
+		records, err := s.trashRepo.FindForExport(ctx, filter, afterID, opts.BatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to load trash records: %w", err)
+		}
+		if len(records) == 0 {
+			break
+		}
+		afterID = records[len(records)-1].ID
+
+		for i := range records {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			trash := &records[i]
+			report.Records++
+
+			// Identical images must not end up in two splits
+			splitKey := trash.ImageSHA256
+			if splitKey == "" {
+				splitKey = trash.ID.String()
+			}
+			if seen[splitKey] {
+				report.SkippedDuplicates++
+				continue
+			}
+			seen[splitKey] = true
+
+			data, err := s.readImage(ctx, trash.ImageKey)
+			if err != nil {
+				s.exportFailure(report, trash, err)
+				continue
+			}
+
+			split := dataset.Assign(splitKey, opts.Seed, ratios)
+			fileName := trash.ID.String() + datasetImageExt(trash)
+			class := dataset.ClassName(trash.Category)
+
+			// Decoded before anything is written, so a failing record leaves no
+			// image without a label row in the archive
+			var image *datasetDetectionImage
+			if detection {
+				if image, err = detectionImage(trash, split, fileName, data); err != nil {
+					s.exportFailure(report, trash, err)
+					continue
+				}
+			}
+
+			if imageFolder {
+				if err := writeStored(archive, path.Join("classification", split, class, fileName), trash.CreatedAt, data); err != nil {
+					return report, err
+				}
+			}
+
+			if detection {
+				if image == nil {
+					report.DetectionSkipped++
+				} else {
+					if err := writeStored(archive, path.Join("detection", "images", split, fileName), trash.CreatedAt, data); err != nil {
+						return report, err
+					}
+					detectionImages = append(detectionImages, *image)
+					report.DetectionImages++
+					report.DetectionBoxes += len(image.detections)
+				}
+			}
+
+			labelsCSV.Write(datasetLabelRow(trash, split, class, fileName))
+			report.Exported++
+			report.Splits[split]++
+			report.Classes[class]++
+			if trash.ReviewStatus == models.ReviewReviewed {
+				report.Reviewed++
+			}
+		}
+		log.Printf("[Dataset] %d records read, %d exported, %d skipped as duplicates, %d failed",
+			report.Records, report.Exported, report.SkippedDuplicates, report.Failed)
+	}
+
+	if detection {
+		if report.DetectionSkipped > 0 {
+			log.Printf("[Dataset] %d records have no stored bounding boxes and were left out of the detection dataset", report.DetectionSkipped)
+		}
+		if err := writeDetectionLabels(archive, detectionImages, opts.Formats); err != nil {
+			return report, err
+		}
+	}
+
+	labelsCSV.Flush()
+	if err := writeDeflated(archive, "labels.csv", labels.Bytes()); err != nil {
+		return report, err
+	}
+	report.FinishedAt = time.Now()
+	manifest, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return report, err
+	}
+	if err := writeDeflated(archive, "manifest.json", manifest); err != nil {
+		return report, err
+	}
+	if err := archive.Close(); err != nil {
+		return report, fmt.Errorf("failed to finish archive: %w", err)
+	}
+	return report, nil
+}
+
+// readImage reads a stored image into memory
+func (s *datasetExportServiceImpl) readImage(ctx context.Context, key string) ([]byte, error) {
+	reader, err := s.storageAdapter.GetObject(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image %s: %w", key, err)
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
+
+// exportFailure logs a record left out of the export and adds it to the report
+func (s *datasetExportServiceImpl) exportFailure(report *dto.DatasetExportReport, trash *models.TrashRecord, err error) {
+	log.Printf("[Dataset] Skipping record %s: %v", trash.ID, err)
+	report.Failed++
+	report.Failures = append(report.Failures, dto.DatasetExportFailure{
+		RecordID: trash.ID,
+		Error:    err.Error(),
+	})
+}
+
+// datasetDetections returns the L0 objects of the record's latest classification
+func datasetDetections(trash *models.TrashRecord) []datasetDetection {
+	if len(trash.Classifications) == 0 {
+		return nil
+	}
+	latest := trash.Classifications[len(trash.Classifications)-1]
+	if len(latest.Detections) > 0 {
+		detections := make([]datasetDetection, 0, len(latest.Detections))
+		for _, d := range latest.Detections {
+			detection := datasetDetection{label: d.Label, confidence: d.Confidence}
+			if d.HasBox() {
+				// Normalized until the image size is known, see detectionImage
+				detection.box = &dataset.Box{X: d.BoxX, Y: d.BoxY, Width: d.BoxWidth, Height: d.BoxHeight}
+			}
+			detections = append(detections, detection)
+		}
+		return detections
+	}
+	if !latest.L0Detected || latest.L0Label == "" {
+		return nil
+	}
+	// Classified before detections were stored: the label only, no bounding box
+	return []datasetDetection{{label: latest.L0Label, confidence: latest.L0Confidence}}
+}
+
+// detectionImage builds the detection dataset entry of a record, or nil when
+// it has no object with a bounding box
+func detectionImage(trash *models.TrashRecord, split, fileName string, data []byte) (*datasetDetectionImage, error) {
+	var boxed []datasetDetection
+	for _, d := range datasetDetections(trash) {
+		if d.box != nil {
+			boxed = append(boxed, d)
+		}
+	}
+	if len(boxed) == 0 {
+		return nil, nil
+	}
+
+	width, height, err := imaging.DecodeSize(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	w, h := float64(width), float64(height)
+	for i, d := range boxed {
+		boxed[i].box = &dataset.Box{X: d.box.X * w, Y: d.box.Y * h, Width: d.box.Width * w, Height: d.box.Height * h}
+	}
+	return &datasetDetectionImage{
+		id:         trash.ID.String(),
+		split:      split,
+		fileName:   fileName,
+		width:      width,
+		height:     height,
+		detections: boxed,
+	}, nil
+}
+
+// writeDetectionLabels writes YOLO label files and data.yaml and the COCO
+// annotation files. Class IDs follow the sorted class names so they are stable.
+func writeDetectionLabels(archive *zip.Writer, images []datasetDetectionImage, formats []string) error {
+	var names []string
+	for _, image := range images {
+		for _, d := range image.detections {
+			if name := dataset.ClassName(d.label); !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	classID := make(map[string]int, len(names))
+	for i, name := range names {
+		classID[name] = i
+	}
+
+	if slices.Contains(formats, dto.DatasetFormatYOLO) {
+		for _, image := range images {
+			var b bytes.Buffer
+			for _, d := range image.detections {
+				b.WriteString(dataset.YOLOLine(classID[dataset.ClassName(d.label)], *d.box, image.width, image.height))
+				b.WriteByte('\n')
+			}
+			if err := writeDeflated(archive, path.Join("detection", "labels", image.split, image.id+".txt"), b.Bytes()); err != nil {
+				return err
+			}
+		}
+		if err := writeDeflated(archive, "detection/data.yaml", []byte(dataset.YOLODataYAML(names))); err != nil {
+			return err
+		}
+	}
+
+	if slices.Contains(formats, dto.DatasetFormatCOCO) {
+		categories := make([]dataset.COCOCategory, len(names))
+		for i, name := range names {
+			// COCO category IDs start at 1
+			categories[i] = dataset.COCOCategory{ID: i + 1, Name: name}
+		}
+		for _, split := range dataset.Splits {
+			file := dataset.COCOFile{
+				Info:        dataset.COCOInfo{Description: "Smart Trash Picker L0 detections (" + split + ")", DateCreated: time.Now().UTC()},
+				Images:      []dataset.COCOImage{},
+				Annotations: []dataset.COCOAnnotation{},
+				Categories:  categories,
+			}
+			for _, image := range images {
+				if image.split != split {
+					continue
+				}
+				imageID := len(file.Images) + 1
+				file.Images = append(file.Images, dataset.COCOImage{
+					ID:       imageID,
+					FileName: path.Join(split, image.fileName),
+					Width:    image.width,
+					Height:   image.height,
+				})
+				for _, d := range image.detections {
+					file.Annotations = append(file.Annotations, dataset.COCOAnnotation{
+						ID:         len(file.Annotations) + 1,
+						ImageID:    imageID,
+						CategoryID: classID[dataset.ClassName(d.label)] + 1,
+						BBox:       [4]float64{d.box.X, d.box.Y, d.box.Width, d.box.Height},
+						Area:       d.box.Width * d.box.Height,
+						Score:      d.confidence,
+					})
+				}
+			}
+			data, err := json.MarshalIndent(file, "", "  ")
+			if err != nil {
+				return err
+			}
+			if err := writeDeflated(archive, "detection/annotations/instances_"+split+".json", data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// datasetLabelRow is the labels.csv row of an exported record
+func datasetLabelRow(trash *models.TrashRecord, split, class, fileName string) []string {
+	source, confidence, modelVersion := "ai", strconv.FormatFloat(trash.Confidence, 'f', 4, 64), ""
+	if trash.ReviewStatus == models.ReviewReviewed {
+		source, confidence = "review", ""
+	}
+	if n := len(trash.Classifications); n > 0 {
+		modelVersion = trash.Classifications[n-1].ModelVersion
+	}
+	return []string{
+		trash.ID.String(), split, class, trash.SubCategory, strconv.Itoa(trash.BinNumber),
+		source, confidence, modelVersion, path.Join("classification", split, class, fileName),
+	}
+}
+
+// datasetImageExt returns the file extension of a record's image
+func datasetImageExt(trash *models.TrashRecord) string {
+	if ext, ok := imageExtensions[trash.ImageContentType]; ok {
+		return "." + ext
+	}
+	return path.Ext(trash.ImageKey)
+}
+
+// writeStored adds already-compressed image data to the archive without recompressing it
+func writeStored(archive *zip.Writer, name string, modified time.Time, data []byte) error {
+	entry, err := archive.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	_, err = entry.Write(data)
+	return err
+}
+
+// writeDeflated adds a compressed text file to the archive
+func writeDeflated(archive *zip.Writer, name string, data []byte) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	_, err = entry.Write(data)
+	return err
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/ports"
+	"gofiber-smart-trash/domain/repositories"
+	"gofiber-smart-trash/domain/services"
+)
+
+const (
+	reclassifyDefaultBatchSize   = 100
+	reclassifyDefaultConcurrency = 4
+)
+
+type reclassificationServiceImpl struct {
+	trashRepo  repositories.TrashRepository
+	classifier services.ClassificationService
+}
+
+// NewReclassificationService creates a new instance of ReclassificationService
+func NewReclassificationService(trashRepo repositories.TrashRepository, classifier services.ClassificationService) services.ReclassificationService {
+	return &reclassificationServiceImpl{
+		trashRepo:  trashRepo,
+		classifier: classifier,
+	}
+}
+
+// Reclassify walks matching records in ID order and classifies each again with
+// at most opts.Concurrency calls in flight. A failed record keeps its previous
+// classification. The run stops early when the AI service refuses calls (circuit
+// breaker open); report.LastID then points before the first unfinished record.
+func (s *reclassificationServiceImpl) Reclassify(ctx context.Context, opts dto.ReclassifyOptions) (*dto.ReclassifyReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = reclassifyDefaultBatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = reclassifyDefaultConcurrency
+	}
+
+	report := &dto.ReclassifyReport{
+		DryRun:    opts.DryRun,
+		Failures:  []dto.ReclassifyFailure{},
+		LastID:    opts.AfterID,
+		StartedAt: time.Now(),
+	}
+	defer func() { report.FinishedAt = time.Now() }()
+
+	filter := repositories.ReclassifyFilter{
+		CreatedFrom:   opts.CreatedFrom,
+		CreatedTo:     opts.CreatedTo,
+		DeviceID:      opts.DeviceID,
+		EmptyCategory: opts.EmptyCategory,
+		HasError:      opts.HasError,
+	}
+	matched, err := s.trashRepo.CountForReclassify(ctx, filter)
+	if err != nil {
+		return report, fmt.Errorf("failed to count trash records: %w", err)
+	}
+	report.Matched = matched
+	if opts.DryRun {
+		return report, nil
+	}
+
+	for {
+		records, err := s.trashRepo.FindForReclassify(ctx, filter, report.LastID, opts.BatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to load trash records: %w", err)
+		}
+		if len(records) == 0 {
+			return report, nil
+		}
+
+		done, abortErr := s.reclassifyBatch(ctx, records, opts.Concurrency, report)
+
+		// Advance the cursor over the finished prefix so a resume retries the rest
+		for i := range records {
+			if !done[i] {
+				break
+			}
+			report.LastID = records[i].ID
+		}
+		log.Printf("[Reclassify] %d/%d records processed (last id %s): %d reclassified, %d changed, %d failed",
+			report.Processed, report.Matched, report.LastID, report.Reclassified, report.Changed, report.Failed)
+		if opts.OnProgress != nil {
+			opts.OnProgress(report)
+		}
+
+		if abortErr != nil {
+			return report, abortErr
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+	}
+}
+
+// reclassifyBatch classifies records with a bounded worker pool and reports
+// which ones finished. It stops handing out records once the context is
+// cancelled or the AI service becomes unavailable.
+func (s *reclassificationServiceImpl) reclassifyBatch(ctx context.Context, records []models.TrashRecord, concurrency int, report *dto.ReclassifyReport) ([]bool, error) {
+	batchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		abortErr error
+	)
+	done := make([]bool, len(records))
+	indexes := make(chan int)
+
+	for range min(concurrency, len(records)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				trash := &records[i]
+				before := *trash
+				_, err := s.classifier.Reclassify(batchCtx, trash)
+
+				mu.Lock()
+				switch {
+				case err == nil:
+					done[i] = true
+					report.Processed++
+					report.Reclassified++
+					if trash.Category != before.Category || trash.SubCategory != before.SubCategory || trash.BinNumber != before.BinNumber {
+						report.Changed++
+					}
+				case batchCtx.Err() != nil:
+					// Interrupted: leave the record for the next run
+				case errors.Is(err, ports.ErrAIUnavailable):
+					if abortErr == nil {
+						abortErr = fmt.Errorf("stopping: %w", err)
+						cancel()
+					}
+				default:
+					log.Printf("[Reclassify] Failed to reclassify record %s: %v", trash.ID, err)
+					done[i] = true
+					report.Processed++
+					report.Failed++
+					report.Failures = append(report.Failures, dto.ReclassifyFailure{
+						RecordID: trash.ID,
+						Error:    err.Error(),
+					})
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for i := range records {
+		if batchCtx.Err() != nil {
+			break
+		}
+		select {
+		case indexes <- i:
+		case <-batchCtx.Done():
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	return done, abortErr
+}
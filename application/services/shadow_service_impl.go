@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/ports"
+	"gofiber-smart-trash/domain/repositories"
+	"gofiber-smart-trash/domain/services"
+	"gofiber-smart-trash/pkg/utils"
+)
+
+// shadowSaveTimeout limits storing one shadow result
+const shadowSaveTimeout = 10 * time.Second
+
+// ShadowServiceConfig holds the tunable behaviour of the shadow service
+type ShadowServiceConfig struct {
+	SampleRate  float64       // Fraction of classifications sent to the shadow model
+	Concurrency int           // Shadow calls in flight; further images are dropped
+	Timeout     time.Duration // Limit for one shadow call
+}
+
+type shadowServiceImpl struct {
+	shadowRepo repositories.ShadowClassificationRepository
+	aiAdapter  ports.AIAdapter // nil when no shadow model is configured
+	config     ShadowServiceConfig
+	slots      chan struct{}
+}
+
+// NewShadowService creates a new instance of ShadowService. aiAdapter is the
+// candidate model; with nil only stored results can be reported.
+func NewShadowService(shadowRepo repositories.ShadowClassificationRepository, aiAdapter ports.AIAdapter, config ShadowServiceConfig) services.ShadowService {
+	if config.SampleRate <= 0 || config.SampleRate > 1 {
+		config.SampleRate = 1
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = 2
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = time.Minute
+	}
+
+	return &shadowServiceImpl{
+		shadowRepo: shadowRepo,
+		aiAdapter:  aiAdapter,
+		config:     config,
+		slots:      make(chan struct{}, config.Concurrency),
+	}
+}
+
+// Submit starts a shadow classification unless the image is not sampled or
+// every slot is busy; production traffic is never slowed down by the candidate
+func (s *shadowServiceImpl) Submit(trash *models.TrashRecord, image ports.ImageData, primary *ports.ClassificationResult) {
+	if s.aiAdapter == nil || primary == nil {
+		return
+	}
+	if s.config.SampleRate < 1 && rand.Float64() >= s.config.SampleRate {
+		return
+	}
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		log.Printf("[Shadow] All %d slots busy, skipping record %s", s.config.Concurrency, trash.ID)
+		return
+	}
+
+	shadow := &models.ShadowClassification{
+		TrashRecordID:       trash.ID,
+		PrimaryCategory:     primary.Category,
+		PrimaryConfidence:   primary.Confidence,
+		PrimaryModelVersion: primary.ModelVersion,
+	}
+	go func() {
+		defer func() { <-s.slots }()
+		s.classify(shadow, image)
+	}()
+}
+
+// classify calls the candidate model and stores its result, or the error, next to the primary result
+func (s *shadowServiceImpl) classify(shadow *models.ShadowClassification, image ports.ImageData) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.Timeout)
+	started := time.Now()
+	result, err := s.aiAdapter.ClassifyImageData(ctx, image)
+	cancel()
+	shadow.LatencyMs = time.Since(started).Milliseconds()
+	if err != nil {
+		log.Printf("[Shadow] Classification of record %s failed: %v", shadow.TrashRecordID, err)
+		shadow.Error = err.Error()
+	} else {
+		shadow.Category = result.Category
+		shadow.SubCategory = result.SubCategory
+		shadow.Confidence = result.Confidence
+		shadow.BinNumber = result.BinNumber
+		shadow.L0Detected = result.L0Detected
+		shadow.L0Label = result.L0Label
+		shadow.L0Confidence = result.L0Confidence
+		shadow.ModelName = result.ModelName
+		shadow.ModelVersion = result.ModelVersion
+		shadow.Agreed = strings.EqualFold(result.Category, shadow.PrimaryCategory)
+		if !shadow.Agreed {
+			log.Printf("[Shadow] Record %s: primary %s, shadow %s (%.2f%%)",
+				shadow.TrashRecordID, shadow.PrimaryCategory, result.Category, result.Confidence*100)
+		}
+	}
+
+	// A timed-out call is still recorded, so saving gets its own deadline
+	ctx, cancel = context.WithTimeout(context.Background(), shadowSaveTimeout)
+	defer cancel()
+	if err := s.shadowRepo.Create(ctx, shadow); err != nil {
+		log.Printf("[Shadow] Failed to save result of record %s: %v", shadow.TrashRecordID, err)
+	}
+}
+
+// AgreementReport builds the per-category agreement and confusion matrix of stored shadow results
+func (s *shadowServiceImpl) AgreementReport(ctx context.Context, req *dto.ShadowReportRequest) (*dto.ShadowReport, error) {
+	filter := repositories.ShadowFilter{ModelVersion: req.ModelVersion}
+	var err error
+	if filter.CreatedFrom, err = utils.ParseDate(req.From); err != nil {
+		return nil, fmt.Errorf("%w: from: %v", services.ErrInvalidDateRange, err)
+	}
+	if filter.CreatedTo, err = utils.ParseDate(req.To); err != nil {
+		return nil, fmt.Errorf("%w: to: %v", services.ErrInvalidDateRange, err)
+	}
+
+	pairs, err := s.shadowRepo.CountPairs(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count shadow results: %w", err)
+	}
+	failed, err := s.shadowRepo.CountFailed(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count shadow failures: %w", err)
+	}
+
+	report := &dto.ShadowReport{
+		From:         filter.CreatedFrom,
+		To:           filter.CreatedTo,
+		ModelVersion: req.ModelVersion,
+		Failed:       failed,
+		Categories:   []dto.ShadowCategoryAgreement{},
+		Confusion:    make(map[string]map[string]int64),
+	}
+	categories := make(map[string]*dto.ShadowCategoryAgreement)
+	category := func(name string) *dto.ShadowCategoryAgreement {
+		if categories[name] == nil {
+			categories[name] = &dto.ShadowCategoryAgreement{Category: name}
+		}
+		return categories[name]
+	}
+
+	for _, pair := range pairs {
+		if report.Confusion[pair.PrimaryCategory] == nil {
+			report.Confusion[pair.PrimaryCategory] = make(map[string]int64)
+		}
+		report.Confusion[pair.PrimaryCategory][pair.Category] += pair.Count
+		report.Compared += pair.Count
+		category(pair.PrimaryCategory).Primary += pair.Count
+		category(pair.Category).Shadow += pair.Count
+		if strings.EqualFold(pair.PrimaryCategory, pair.Category) {
+			report.Agreed += pair.Count
+			category(pair.PrimaryCategory).Agreed += pair.Count
+		}
+	}
+
+	if report.Compared > 0 {
+		report.AgreementRate = float64(report.Agreed) / float64(report.Compared)
+	}
+	for _, agreement := range categories {
+		if agreement.Primary > 0 {
+			agreement.AgreementRate = float64(agreement.Agreed) / float64(agreement.Primary)
+		}
+		report.Categories = append(report.Categories, *agreement)
+	}
+	slices.SortFunc(report.Categories, func(a, b dto.ShadowCategoryAgreement) int {
+		return strings.Compare(a.Category, b.Category)
+	})
+	return report, nil
+}
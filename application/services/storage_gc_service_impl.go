@@ -0,0 +1,165 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/ports"
+	"gofiber-smart-trash/domain/repositories"
+	"gofiber-smart-trash/domain/services"
+)
+
+const (
+	// GCModeDelete permanently removes orphaned images
+	GCModeDelete = "delete"
+	// GCModeQuarantine moves orphaned images under quarantine/ for manual review
+	GCModeQuarantine = "quarantine"
+
+	gcDefaultPrefix  = "trash/"
+	gcQuarantineRoot = "quarantine/"
+	gcBatchSize      = 500
+)
+
+type storageGCServiceImpl struct {
+	trashRepo      repositories.TrashRepository
+	uploadRepo     repositories.UploadSessionRepository
+	storageAdapter ports.StorageAdapter
+}
+
+// NewStorageGCService creates a new instance of StorageGCService
+func NewStorageGCService(trashRepo repositories.TrashRepository, uploadRepo repositories.UploadSessionRepository, storageAdapter ports.StorageAdapter) services.StorageGCService {
+	return &storageGCServiceImpl{
+		trashRepo:      trashRepo,
+		uploadRepo:     uploadRepo,
+		storageAdapter: storageAdapter,
+	}
+}
+
+// CollectOrphans lists stored images, cross-checks them against trash records,
+// and deletes or quarantines the ones older than the grace period
+func (s *storageGCServiceImpl) CollectOrphans(ctx context.Context, opts dto.StorageGCOptions) (*dto.StorageGCReport, error) {
+	if opts.Prefix == "" {
+		opts.Prefix = gcDefaultPrefix
+	}
+	if opts.Mode == "" {
+		opts.Mode = GCModeQuarantine
+	}
+	if opts.Mode != GCModeDelete && opts.Mode != GCModeQuarantine {
+		return nil, fmt.Errorf("unknown GC mode '%s'", opts.Mode)
+	}
+
+	report := &dto.StorageGCReport{
+		DryRun:      opts.DryRun,
+		Mode:        opts.Mode,
+		Prefix:      opts.Prefix,
+		GracePeriod: opts.GracePeriod.String(),
+		Orphans:     []dto.OrphanObject{},
+		StartedAt:   time.Now(),
+	}
+	cutoff := report.StartedAt.Add(-opts.GracePeriod)
+
+	// Check candidates against the database in batches
+	batch := make([]ports.ObjectInfo, 0, gcBatchSize)
+	err := s.storageAdapter.ListObjects(ctx, opts.Prefix, func(obj ports.ObjectInfo) error {
+		report.Scanned++
+		if obj.LastModified.After(cutoff) {
+			report.TooRecent++
+			return nil
+		}
+
+		batch = append(batch, obj)
+		if len(batch) < gcBatchSize {
+			return nil
+		}
+		err := s.processBatch(ctx, batch, opts, report)
+		batch = batch[:0]
+		return err
+	})
+	if err == nil && len(batch) > 0 {
+		err = s.processBatch(ctx, batch, opts, report)
+	}
+	if err != nil {
+		return report, fmt.Errorf("failed to collect orphaned images: %w", err)
+	}
+
+	// Pending upload sessions whose URL has expired will never be used
+	if !opts.DryRun {
+		expired, err := s.uploadRepo.ExpirePending(ctx, report.StartedAt)
+		if err != nil {
+			return report, fmt.Errorf("failed to expire upload sessions: %w", err)
+		}
+		report.ExpiredSessions = expired
+	}
+
+	report.FinishedAt = time.Now()
+	log.Printf("[GC] scanned=%d too_recent=%d referenced=%d orphaned=%d removed=%d failed=%d dry_run=%v",
+		report.Scanned, report.TooRecent, report.Referenced, report.Orphaned, report.Removed, report.Failed, report.DryRun)
+
+	return report, nil
+}
+
+// processBatch finds the unreferenced objects in a batch and removes them
+func (s *storageGCServiceImpl) processBatch(ctx context.Context, batch []ports.ObjectInfo, opts dto.StorageGCOptions, report *dto.StorageGCReport) error {
+	keys := make([]string, len(batch))
+	urls := make([]string, len(batch))
+	for i, obj := range batch {
+		keys[i] = obj.Key
+		urls[i] = s.storageAdapter.GeneratePublicURL(obj.Key)
+	}
+
+	// Private-mode records only carry the key, older records only the URL
+	existingKeys, err := s.trashRepo.FindExistingImageKeys(ctx, keys)
+	if err != nil {
+		return fmt.Errorf("failed to look up trash records: %w", err)
+	}
+	existingURLs, err := s.trashRepo.FindExistingImageURLs(ctx, urls)
+	if err != nil {
+		return fmt.Errorf("failed to look up trash records: %w", err)
+	}
+	referenced := make(map[string]bool, len(existingKeys)+len(existingURLs))
+	for _, value := range append(existingKeys, existingURLs...) {
+		referenced[value] = true
+	}
+
+	for i, obj := range batch {
+		if referenced[obj.Key] || referenced[urls[i]] {
+			report.Referenced++
+			continue
+		}
+
+		report.Orphaned++
+		report.OrphanedBytes += obj.Size
+		orphan := dto.OrphanObject{
+			Key:          obj.Key,
+			Size:         obj.Size,
+			LastModified: obj.LastModified,
+		}
+
+		if opts.DryRun {
+			orphan.Action = "would_" + opts.Mode
+		} else if err := s.removeOrphan(ctx, obj.Key, opts.Mode); err != nil {
+			log.Printf("[GC] Failed to %s %s: %v", opts.Mode, obj.Key, err)
+			orphan.Action = "failed"
+			orphan.Error = err.Error()
+			report.Failed++
+		} else {
+			orphan.Action = map[string]string{GCModeDelete: "deleted", GCModeQuarantine: "quarantined"}[opts.Mode]
+			report.Removed++
+		}
+		report.Orphans = append(report.Orphans, orphan)
+	}
+	return nil
+}
+
+// removeOrphan deletes an object, copying it under quarantine/ first in quarantine mode
+func (s *storageGCServiceImpl) removeOrphan(ctx context.Context, key, mode string) error {
+	if mode == GCModeQuarantine {
+		if err := s.storageAdapter.CopyObject(ctx, key, gcQuarantineRoot+key); err != nil {
+			return err
+		}
+	}
+	return s.storageAdapter.DeleteObject(ctx, key)
+}
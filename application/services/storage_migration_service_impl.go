@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/ports"
+	"gofiber-smart-trash/domain/repositories"
+	"gofiber-smart-trash/domain/services"
+
+	"github.com/google/uuid"
+)
+
+const migrationDefaultBatchSize = 100
+
+type storageMigrationServiceImpl struct {
+	trashRepo repositories.TrashRepository
+	source    ports.StorageAdapter
+	target    ports.StorageAdapter
+}
+
+// NewStorageMigrationService creates a new instance of StorageMigrationService
+func NewStorageMigrationService(trashRepo repositories.TrashRepository, source, target ports.StorageAdapter) services.StorageMigrationService {
+	return &storageMigrationServiceImpl{
+		trashRepo: trashRepo,
+		source:    source,
+		target:    target,
+	}
+}
+
+// Migrate walks trash records in ID order, copies their original image and
+// variants to the target storage, verifies each copy by SHA-256, and rewrites
+// image_url batch by batch. Re-running is safe: identical objects are skipped.
+func (s *storageMigrationServiceImpl) Migrate(ctx context.Context, opts dto.StorageMigrationOptions) (*dto.StorageMigrationReport, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = migrationDefaultBatchSize
+	}
+
+	report := &dto.StorageMigrationReport{
+		DryRun:    opts.DryRun,
+		Failures:  []dto.MigrationFailure{},
+		LastID:    opts.AfterID,
+		StartedAt: time.Now(),
+	}
+	defer func() { report.FinishedAt = time.Now() }()
+
+	for {
+		records, err := s.trashRepo.FindBatchAfterID(ctx, report.LastID, opts.BatchSize)
+		if err != nil {
+			return report, fmt.Errorf("failed to load trash records: %w", err)
+		}
+		if len(records) == 0 {
+			return report, nil
+		}
+
+		locations := make([]repositories.ImageLocation, 0, len(records))
+		lastID := report.LastID
+		for i := range records {
+			// Stop between records so the batch can still be committed
+			if ctx.Err() != nil {
+				break
+			}
+			location := s.migrateRecord(ctx, &records[i], opts.DryRun, report)
+			// Interrupted mid-record: leave the cursor before it so it is retried on resume
+			if ctx.Err() != nil {
+				break
+			}
+			if location != nil {
+				locations = append(locations, *location)
+			}
+			lastID = records[i].ID
+		}
+
+		if !opts.DryRun && len(locations) > 0 {
+			if err := s.trashRepo.UpdateImageLocations(ctx, locations); err != nil {
+				return report, fmt.Errorf("failed to rewrite image URLs: %w", err)
+			}
+		}
+		report.URLsRewritten += len(locations)
+		report.LastID = lastID
+		log.Printf("[Migrate] %d records processed (last id %s): %d copied, %d skipped, %d failed",
+			report.Records, report.LastID, report.ObjectsCopied, report.ObjectsSkipped, report.Failed)
+
+		if opts.OnBatch != nil {
+			if err := opts.OnBatch(report); err != nil {
+				return report, err
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+	}
+}
+
+// migrateRecord copies every object of one record and returns the location to
+// store on it, or nil when nothing needs rewriting or the copy failed
+func (s *storageMigrationServiceImpl) migrateRecord(ctx context.Context, trash *models.TrashRecord, dryRun bool, report *dto.StorageMigrationReport) *repositories.ImageLocation {
+	report.Records++
+
+	// Legacy records only have the public URL of their image
+	sourcePrefix := s.source.GeneratePublicURL("")
+	targetPrefix := s.target.GeneratePublicURL("")
+	imageKey := trash.ImageKey
+	if imageKey == "" && strings.HasPrefix(trash.ImageURL, sourcePrefix) {
+		imageKey = strings.TrimPrefix(trash.ImageURL, sourcePrefix)
+	}
+	if imageKey == "" {
+		if sourcePrefix != targetPrefix && strings.HasPrefix(trash.ImageURL, targetPrefix) {
+			report.AlreadyMigrated++
+			return nil
+		}
+		s.recordFailure(report, trash.ID, "", errors.New("record has no image key and image_url is not in source storage"))
+		return nil
+	}
+
+	// Records are rewritten only after all their objects were copied
+	targetURL := ""
+	if trash.ImageURL != "" {
+		targetURL = s.target.GeneratePublicURL(imageKey)
+	}
+	if sourcePrefix != targetPrefix && trash.ImageURL != "" && trash.ImageURL == targetURL && trash.ImageKey != "" {
+		report.AlreadyMigrated++
+		return nil
+	}
+
+	for _, key := range []string{imageKey, trash.ThumbnailKey, trash.MediumKey} {
+		if key == "" {
+			continue
+		}
+		copied, size, err := s.copyObject(ctx, key, dryRun)
+		if err != nil {
+			s.recordFailure(report, trash.ID, key, err)
+			return nil
+		}
+		if copied {
+			report.ObjectsCopied++
+			report.BytesCopied += size
+		} else {
+			report.ObjectsSkipped++
+		}
+	}
+	report.Migrated++
+
+	if trash.ImageURL == targetURL && trash.ImageKey == imageKey {
+		return nil
+	}
+	return &repositories.ImageLocation{
+		ID:       trash.ID,
+		ImageURL: targetURL,
+		ImageKey: imageKey,
+	}
+}
+
+// copyObject streams one object from source to target and verifies the copy.
+// Returns false when an identical object already exists at the target.
+func (s *storageMigrationServiceImpl) copyObject(ctx context.Context, key string, dryRun bool) (bool, int64, error) {
+	srcInfo, err := s.source.StatObject(ctx, key)
+	if err != nil {
+		return false, 0, fmt.Errorf("source: %w", err)
+	}
+
+	dstInfo, err := s.target.StatObject(ctx, key)
+	if err != nil && !errors.Is(err, ports.ErrObjectNotFound) {
+		return false, 0, fmt.Errorf("target: %w", err)
+	}
+	if err == nil && dstInfo.Size == srcInfo.Size {
+		srcSum, err := objectSHA256(ctx, s.source, key)
+		if err != nil {
+			return false, 0, fmt.Errorf("source: %w", err)
+		}
+		dstSum, err := objectSHA256(ctx, s.target, key)
+		if err != nil {
+			return false, 0, fmt.Errorf("target: %w", err)
+		}
+		if srcSum == dstSum {
+			return false, 0, nil
+		}
+	}
+	if dryRun {
+		return true, srcInfo.Size, nil
+	}
+
+	reader, err := s.source.GetObject(ctx, key)
+	if err != nil {
+		return false, 0, fmt.Errorf("source: %w", err)
+	}
+	defer reader.Close()
+
+	hasher := sha256.New()
+	if err := s.target.PutObject(ctx, key, io.TeeReader(reader, hasher), srcInfo.ContentType); err != nil {
+		return false, 0, fmt.Errorf("target: %w", err)
+	}
+
+	// Read the copy back rather than trusting provider ETags, which differ between providers
+	dstSum, err := objectSHA256(ctx, s.target, key)
+	if err != nil {
+		return false, 0, fmt.Errorf("target: %w", err)
+	}
+	if srcSum := hex.EncodeToString(hasher.Sum(nil)); srcSum != dstSum {
+		return false, 0, fmt.Errorf("checksum mismatch after copy: source %s, target %s", srcSum, dstSum)
+	}
+	return true, srcInfo.Size, nil
+}
+
+// recordFailure logs a record that could not be migrated and adds it to the report
+func (s *storageMigrationServiceImpl) recordFailure(report *dto.StorageMigrationReport, id uuid.UUID, key string, err error) {
+	log.Printf("[Migrate] Failed to migrate record %s (%s): %v", id, key, err)
+	report.Failed++
+	report.Failures = append(report.Failures, dto.MigrationFailure{
+		RecordID: id,
+		Key:      key,
+		Error:    err.Error(),
+	})
+}
+
+// objectSHA256 returns the hex SHA-256 of a stored object
+func objectSHA256(ctx context.Context, adapter ports.StorageAdapter, key string) (string, error) {
+	reader, err := adapter.GetObject(ctx, key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, reader); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
@@ -1,9 +1,19 @@
 package services
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
 	"time"
 
 	"gofiber-smart-trash/domain/dto"
@@ -11,109 +21,647 @@ import (
 	"gofiber-smart-trash/domain/ports"
 	"gofiber-smart-trash/domain/repositories"
 	"gofiber-smart-trash/domain/services"
+	"gofiber-smart-trash/pkg/imaging"
+	"gofiber-smart-trash/pkg/utils"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// TrashServiceConfig holds the tunable behaviour of the trash service
+type TrashServiceConfig struct {
+	UploadExpiry        time.Duration // Lifetime of presigned upload URLs
+	MaxUploadBytes      int64         // Maximum image size accepted by upload URLs
+	AllowedContentTypes []string      // Image content types devices may upload
+
+	// Private bucket mode: records store only object keys and responses carry signed read URLs
+	PrivateBucket bool
+	SignedURLTTL  time.Duration
+
+	// Resized image variants
+	VariantsEnabled bool
+	ThumbnailSize   int // Longest side in pixels
+	MediumSize      int // Longest side in pixels
+
+	// EXIF handling: capture time, orientation and GPS are kept on the record,
+	// and the stored image is rewritten without metadata when StripMetadata is set
+	StripMetadata          bool
+	LocationMismatchMeters float64 // EXIF GPS further than this from the submitted coordinates is flagged, 0 disables
+
+	// Duplicate submissions from the same device within DedupWindow return the existing record
+	DedupEnabled        bool
+	DedupWindow         time.Duration
+	DedupPHashThreshold int // Max differing perceptual hash bits for a near-duplicate, negative for exact matches only
+
+	// Async classification: records are saved as pending and classified by queue workers
+	AsyncClassification bool
+}
+
+const (
+	variantThumbnail = "thumbnail"
+	variantMedium    = "medium"
+	variantQuality   = 80
+	variantTimeout   = time.Minute
+)
+
+// imageExtensions maps allowed image content types to object key extensions
+var imageExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+	"image/webp": "webp",
+}
+
+// imageFingerprint identifies image content for duplicate detection
+type imageFingerprint struct {
+	sha256 string // Hex SHA-256 of the stored bytes, i.e. after EXIF stripping
+	phash  *int64 // Perceptual hash, nil if the image could not be decoded
+}
+
 type trashServiceImpl struct {
 	trashRepo      repositories.TrashRepository
+	uploadRepo     repositories.UploadSessionRepository
+	reviewRepo     repositories.ClassificationReviewRepository
 	storageAdapter ports.StorageAdapter
-	aiAdapter      ports.AIAdapter
+	classifier     services.ClassificationService
+	config         TrashServiceConfig
 }
 
 // NewTrashService creates a new instance of TrashService
-func NewTrashService(trashRepo repositories.TrashRepository, storageAdapter ports.StorageAdapter, aiAdapter ports.AIAdapter) services.TrashService {
+func NewTrashService(trashRepo repositories.TrashRepository, uploadRepo repositories.UploadSessionRepository, reviewRepo repositories.ClassificationReviewRepository, storageAdapter ports.StorageAdapter, classifier services.ClassificationService, config TrashServiceConfig) services.TrashService {
+	if config.UploadExpiry == 0 {
+		config.UploadExpiry = 15 * time.Minute
+	}
+	if len(config.AllowedContentTypes) == 0 {
+		config.AllowedContentTypes = []string{"image/jpeg", "image/png", "image/webp"}
+	}
+	if config.SignedURLTTL == 0 {
+		config.SignedURLTTL = 15 * time.Minute
+	}
+	if config.ThumbnailSize == 0 {
+		config.ThumbnailSize = 200
+	}
+	if config.MediumSize == 0 {
+		config.MediumSize = 800
+	}
+	if config.DedupWindow == 0 {
+		config.DedupWindow = 10 * time.Minute
+	}
+
 	return &trashServiceImpl{
 		trashRepo:      trashRepo,
+		uploadRepo:     uploadRepo,
+		reviewRepo:     reviewRepo,
 		storageAdapter: storageAdapter,
-		aiAdapter:      aiAdapter,
+		classifier:     classifier,
+		config:         config,
 	}
 }
 
 // GenerateUploadURL generates a presigned URL for uploading trash images
-func (s *trashServiceImpl) GenerateUploadURL(ctx context.Context, deviceID string) (*dto.UploadURLResponse, error) {
-	// Generate unique key: trash/{device_id}/{timestamp}.jpg
-	timestamp := time.Now().UnixMilli()
-	key := fmt.Sprintf("trash/%s/%d.jpg", deviceID, timestamp)
+func (s *trashServiceImpl) GenerateUploadURL(ctx context.Context, req *dto.UploadURLRequest) (*dto.UploadURLResponse, error) {
+	contentType := req.ContentType
+	if contentType == "" {
+		contentType = "image/jpeg"
+	}
+	if !slices.Contains(s.config.AllowedContentTypes, contentType) {
+		return nil, fmt.Errorf("%w: content type %s is not allowed", services.ErrInvalidUpload, contentType)
+	}
+	ext, ok := imageExtensions[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: content type %s is not an image", services.ErrInvalidUpload, contentType)
+	}
+	if req.ContentLength < 0 || (s.config.MaxUploadBytes > 0 && req.ContentLength > s.config.MaxUploadBytes) {
+		return nil, fmt.Errorf("%w: content length must not exceed %d bytes", services.ErrInvalidUpload, s.config.MaxUploadBytes)
+	}
+
+	// Content-addressed key when the device sends its checksum: trash/{device_id}/{sha256}.{ext}
+	// otherwise a unique key: trash/{device_id}/{timestamp}.{ext}
+	key := fmt.Sprintf("trash/%s/%d.%s", req.DeviceID, time.Now().UnixMilli(), ext)
+	if req.ChecksumSHA256 != "" {
+		sum, err := base64.StdEncoding.DecodeString(req.ChecksumSHA256)
+		if err != nil || len(sum) != sha256.Size {
+			return nil, fmt.Errorf("%w: checksum_sha256 must be a base64-encoded SHA-256 digest", services.ErrInvalidUpload)
+		}
+		key = fmt.Sprintf("trash/%s/%s.%s", req.DeviceID, hex.EncodeToString(sum), ext)
+	}
 
 	// Generate presigned URL using storage adapter
-	urlResp, err := s.storageAdapter.GeneratePresignedUploadURL(ctx, key, 15*time.Minute)
+	urlResp, err := s.storageAdapter.GeneratePresignedUploadURL(ctx, key, ports.UploadOptions{
+		Expiry:         s.config.UploadExpiry,
+		ContentType:    contentType,
+		ContentLength:  req.ContentLength,
+		MaxBytes:       s.config.MaxUploadBytes,
+		ContentMD5:     req.ContentMD5,
+		ChecksumSHA256: req.ChecksumSHA256,
+	})
+	if errors.Is(err, ports.ErrContentLengthRequired) || errors.Is(err, ports.ErrUnsupportedChecksum) {
+		return nil, fmt.Errorf("%w: %v", services.ErrInvalidUpload, err)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate presigned URL: %w", err)
 	}
 
+	// Track the issued URL so POST /api/trash can reference it by upload_id
+	session, err := s.trackUploadSession(ctx, req.DeviceID, key, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	imageURL, err := s.readableURL(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
 	return &dto.UploadURLResponse{
-		UploadURL: urlResp.UploadURL,
-		ImageURL:  urlResp.PublicURL,
-		ExpiresIn: urlResp.ExpiresIn,
+		UploadID:      session.ID,
+		UploadURL:     urlResp.UploadURL,
+		UploadMethod:  urlResp.Method,
+		UploadHeaders: urlResp.Headers,
+		FormFields:    urlResp.FormFields,
+		ImageURL:      imageURL,
+		MaxBytes:      s.config.MaxUploadBytes,
+		ExpiresIn:     urlResp.ExpiresIn,
 	}, nil
 }
 
 // CreateTrashRecord creates a new trash record in the database with AI classification (SYNC mode)
 func (s *trashServiceImpl) CreateTrashRecord(ctx context.Context, req *dto.CreateTrashRequest) (*dto.TrashResponse, error) {
+	// Derive the image URL from the upload session when one is referenced
+	imageURL := req.ImageURL
+	var session *models.UploadSession
+	if req.UploadID != "" {
+		var err error
+		session, err = s.findUploadSession(ctx, req.DeviceID, req.UploadID)
+		if err != nil {
+			return nil, err
+		}
+		// A retried submission of an already used upload gets the record it created
+		if session.Status == models.UploadSessionUsed {
+			if !s.config.DedupEnabled || session.TrashRecordID == nil {
+				return nil, services.ErrUploadAlreadyUsed
+			}
+			return s.duplicateOf(ctx, *session.TrashRecordID)
+		}
+		imageURL = s.storageAdapter.GeneratePublicURL(session.ObjectKey)
+	}
+
+	// Make sure the image was actually uploaded by this device to our bucket
+	object, err := s.verifyUploadedImage(ctx, req.DeviceID, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.readObject(ctx, object.Key)
+	if err != nil {
+		return nil, err
+	}
+
 	trash := &models.TrashRecord{
-		DeviceID:  req.DeviceID,
-		ImageURL:  req.ImageURL,
-		Latitude:  req.Latitude,
-		Longitude: req.Longitude,
+		ID:               uuid.New(),
+		DeviceID:         req.DeviceID,
+		Latitude:         req.Latitude,
+		Longitude:        req.Longitude,
+		ImageKey:         object.Key,
+		ImageSize:        object.Size,
+		ImageContentType: object.ContentType,
+		ImageETag:        object.ETag,
 	}
 
-	// SYNC Mode: Call AI service to classify the image before responding
+	// EXIF is removed before hashing so the hash always describes the stored bytes;
+	// stripping an already stripped image gives the same bytes, so retries still match
+	contentType := http.DetectContentType(data)
+	stripped, err := s.applyImageMetadata(trash, data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", services.ErrInvalidImage, err)
+	}
+	fingerprint := fingerprintImage(stripped)
+	duplicate, err := s.findDuplicate(ctx, req.DeviceID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate != nil {
+		log.Printf("[Dedup] %s duplicates trash record %s", object.Key, duplicate.ID)
+		// Point the session at the existing record so retries with this upload_id resolve to it
+		if session != nil {
+			claimed, err := s.uploadRepo.Claim(ctx, session.ID, duplicate.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to claim upload session: %w", err)
+			}
+			if !claimed {
+				// A concurrent submission of the same upload got there first: answer with its record
+				return s.claimedUploadResponse(ctx, req.DeviceID, req.UploadID)
+			}
+		}
+		return s.toDuplicateResponse(ctx, duplicate)
+	}
+
+	trash.ImageSHA256 = fingerprint.sha256
+	trash.ImagePHash = fingerprint.phash
+	if !bytes.Equal(stripped, data) {
+		if err := s.replaceUploadedImage(ctx, trash, data, stripped, contentType); err != nil {
+			return nil, err
+		}
+	}
+	trash.ImageURL = s.storedImageURL(trash.ImageKey)
+
+	return s.classifyAndSave(ctx, trash, session)
+}
+
+// replaceUploadedImage stores the copy of an uploaded image without EXIF. A
+// content-addressed key names the uploaded bytes, so the copy moves to the key
+// of its own hash and the original is deleted; other keys are overwritten.
+func (s *trashServiceImpl) replaceUploadedImage(ctx context.Context, trash *models.TrashRecord, original, stripped []byte, contentType string) error {
+	uploadedKey := trash.ImageKey
+	key := uploadedKey
+	originalSum := sha256.Sum256(original)
+	if name := path.Base(uploadedKey); strings.TrimSuffix(name, path.Ext(name)) == hex.EncodeToString(originalSum[:]) {
+		key = path.Join(path.Dir(uploadedKey), trash.ImageSHA256+path.Ext(uploadedKey))
+	}
+
+	if err := s.storageAdapter.PutObject(ctx, key, bytes.NewReader(stripped), contentType); err != nil {
+		return fmt.Errorf("failed to store stripped image: %w", err)
+	}
+	object, err := s.storageAdapter.StatObject(ctx, key)
+	if err != nil {
+		return fmt.Errorf("failed to verify stored image: %w", err)
+	}
+	trash.ImageKey = key
+	trash.ImageSize = object.Size
+	trash.ImageETag = object.ETag
+
+	if key != uploadedKey {
+		// Left behind it is an orphan for the storage GC, so a failure is not fatal
+		if err := s.storageAdapter.DeleteObject(ctx, uploadedKey); err != nil {
+			log.Printf("Warning: failed to delete original image %s: %v", uploadedKey, err)
+		}
+	}
+	return nil
+}
+
+// UploadTrashRecord stores an image sent directly to the API, then classifies
+// and saves it exactly like CreateTrashRecord
+func (s *trashServiceImpl) UploadTrashRecord(ctx context.Context, req *dto.UploadTrashRequest, image io.Reader, size int64) (*dto.TrashResponse, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: image is empty", services.ErrInvalidUpload)
+	}
+	if s.config.MaxUploadBytes > 0 && size > s.config.MaxUploadBytes {
+		return nil, fmt.Errorf("%w: image must not exceed %d bytes", services.ErrInvalidUpload, s.config.MaxUploadBytes)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(image, size))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+
+	// Sniff the real content type instead of trusting the multipart header
+	contentType := http.DetectContentType(data)
+	ext, ok := imageExtensions[contentType]
+	if !ok || !slices.Contains(s.config.AllowedContentTypes, contentType) {
+		return nil, fmt.Errorf("%w: content type %s is not allowed", services.ErrInvalidUpload, contentType)
+	}
+
+	trash := &models.TrashRecord{
+		ID:               uuid.New(),
+		DeviceID:         req.DeviceID,
+		Latitude:         req.Latitude,
+		Longitude:        req.Longitude,
+		ImageContentType: contentType,
+	}
+
+	// Only the copy without EXIF is ever stored, so it is what gets hashed
+	stripped, err := s.applyImageMetadata(trash, data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", services.ErrInvalidUpload, err)
+	}
+
+	// Check for a duplicate before storing anything
+	fingerprint := fingerprintImage(stripped)
+	duplicate, err := s.findDuplicate(ctx, req.DeviceID, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if duplicate != nil {
+		log.Printf("[Dedup] Upload from %s duplicates trash record %s", req.DeviceID, duplicate.ID)
+		return s.toDuplicateResponse(ctx, duplicate)
+	}
+
+	// Content-addressed key: trash/{device_id}/{sha256}.{ext}
+	key := fmt.Sprintf("trash/%s/%s.%s", req.DeviceID, fingerprint.sha256, ext)
+	trash.ImageKey = key
+	trash.ImageURL = s.storedImageURL(key)
+	trash.ImageSHA256 = fingerprint.sha256
+	trash.ImagePHash = fingerprint.phash
+
+	if err := s.storageAdapter.PutObject(ctx, key, bytes.NewReader(stripped), contentType); err != nil {
+		return nil, fmt.Errorf("failed to store image: %w", err)
+	}
+
+	object, err := s.storageAdapter.StatObject(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify stored image: %w", err)
+	}
+	trash.ImageSize = object.Size
+	trash.ImageETag = object.ETag
+
+	return s.classifyAndSave(ctx, trash, nil)
+}
+
+// classifyAndSave runs AI classification on a new record (or queues it in async
+// mode) and persists it, claiming the upload session first when the image came from one
+func (s *trashServiceImpl) classifyAndSave(ctx context.Context, trash *models.TrashRecord, session *models.UploadSession) (*dto.TrashResponse, error) {
 	var classifyResult *ports.ClassificationResult
-	var classifyErr error
-	var message string
-	var l0Detected bool
-	var l0Label string
-	var l0Confidence float64
-
-	if s.aiAdapter != nil {
-		log.Printf("[AI] Classifying image: %s", req.ImageURL)
-		classifyResult, classifyErr = s.aiAdapter.ClassifyImage(ctx, req.ImageURL)
-
-		if classifyErr != nil {
-			log.Printf("[AI] Classification failed: %v", classifyErr)
-			trash.ClassifyError = classifyErr.Error()
+	var job *models.ClassificationJob
+
+	if s.classifier != nil {
+		if s.config.AsyncClassification {
+			// Respond right away; a queue worker classifies the record
+			trash.ClassificationStatus = models.ClassificationPending
+			job = &models.ClassificationJob{
+				TrashRecordID: trash.ID,
+				Status:        models.ClassificationJobQueued,
+				RunAt:         time.Now(),
+			}
 		} else {
-			log.Printf("[AI] L0 (YOLO): detected=%v, label=%s (%.2f%%)",
-				classifyResult.L0Detected, classifyResult.L0Label, classifyResult.L0Confidence*100)
-			log.Printf("[AI] L1 (Trash-Net): %s (%.2f%%)",
-				classifyResult.Category, classifyResult.Confidence*100)
-			trash.Category = classifyResult.Category
-			trash.SubCategory = classifyResult.SubCategory
-			trash.Confidence = classifyResult.Confidence
-			trash.BinNumber = classifyResult.BinNumber
-			trash.BinLabel = classifyResult.BinLabel
-			trash.ClassifiedAt = time.Now()
-			message = classifyResult.Message
-			l0Detected = classifyResult.L0Detected
-			l0Label = classifyResult.L0Label
-			l0Confidence = classifyResult.L0Confidence
-		}
-	}
-
-	if err := s.trashRepo.Create(ctx, trash); err != nil {
+			// SYNC Mode: classify before responding; a failure is recorded on the record
+			classifyResult, _ = s.classifier.Classify(ctx, trash)
+		}
+	}
+
+	// Claim the upload session so the same upload cannot create a second record
+	if session != nil {
+		claimed, err := s.uploadRepo.Claim(ctx, session.ID, trash.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to claim upload session: %w", err)
+		}
+		if !claimed {
+			return nil, services.ErrUploadAlreadyUsed
+		}
+	}
+
+	var err error
+	if job != nil {
+		err = s.trashRepo.CreateWithClassificationJob(ctx, trash, job)
+	} else {
+		err = s.trashRepo.Create(ctx, trash)
+	}
+	if err != nil {
+		if session != nil {
+			if releaseErr := s.uploadRepo.Release(ctx, session.ID); releaseErr != nil {
+				log.Printf("Warning: failed to release upload session %s: %v", session.ID, releaseErr)
+			}
+		}
 		return nil, fmt.Errorf("failed to create trash record: %w", err)
 	}
 
-	return &dto.TrashResponse{
-		ID:            trash.ID,
-		DeviceID:      trash.DeviceID,
-		ImageURL:      trash.ImageURL,
-		Latitude:      trash.Latitude,
-		Longitude:     trash.Longitude,
-		Category:      trash.Category,
-		SubCategory:   trash.SubCategory,
-		Confidence:    trash.Confidence,
-		BinNumber:     trash.BinNumber,
-		BinLabel:      trash.BinLabel,
-		Message:       message,
-		L0Detected:    l0Detected,
-		L0Label:       l0Label,
-		L0Confidence:  l0Confidence,
-		ClassifyError: trash.ClassifyError,
-		ClassifiedAt:  trash.ClassifiedAt,
-		CreatedAt:     trash.CreatedAt,
-	}, nil
+	// Only now that the record exists, so duplicates and failed creates stay out of the shadow report
+	if classifyResult != nil {
+		s.classifier.SubmitShadow(ctx, trash, classifyResult)
+	}
+
+	// Thumbnails are generated in the background so the device is not kept waiting
+	if s.config.VariantsEnabled {
+		go s.generateVariants(trash.ID, trash.ImageKey)
+	}
+
+	response, err := s.toTrashResponse(ctx, trash)
+	if err != nil {
+		return nil, err
+	}
+	applyClassificationResult(&response, classifyResult)
+	return &response, nil
+}
+
+// applyClassificationResult copies the parts of a fresh AI result that are not stored on the record
+func applyClassificationResult(response *dto.TrashResponse, result *ports.ClassificationResult) {
+	if result == nil {
+		return
+	}
+	response.Message = result.Message
+	response.L0Detected = result.L0Detected
+	response.L0Label = result.L0Label
+	response.L0Confidence = result.L0Confidence
+}
+
+// findUploadSession loads an upload session and checks it belongs to the device
+// and has not expired. Used sessions are returned for the caller to answer with
+// the record they created.
+func (s *trashServiceImpl) findUploadSession(ctx context.Context, deviceID, uploadID string) (*models.UploadSession, error) {
+	id, err := uuid.Parse(uploadID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid upload_id", services.ErrInvalidImage)
+	}
+
+	session, err := s.uploadRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: upload session not found", services.ErrInvalidImage)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get upload session: %w", err)
+	}
+
+	if session.DeviceID != deviceID {
+		return nil, fmt.Errorf("%w: upload session belongs to another device", services.ErrInvalidImage)
+	}
+	// Checked here too, as pending sessions are only marked expired periodically
+	if session.Status == models.UploadSessionExpired ||
+		(session.Status == models.UploadSessionPending && time.Now().After(session.ExpiresAt)) {
+		return nil, fmt.Errorf("%w: upload session has expired", services.ErrInvalidImage)
+	}
+
+	return session, nil
+}
+
+// trackUploadSession records an issued upload URL. Content-addressed keys can be
+// requested again on retry, in which case the existing session is renewed and reused.
+func (s *trashServiceImpl) trackUploadSession(ctx context.Context, deviceID, key, contentType string) (*models.UploadSession, error) {
+	expiresAt := time.Now().Add(s.config.UploadExpiry)
+
+	session, err := s.uploadRepo.FindByObjectKey(ctx, key)
+	if err == nil {
+		if err := s.uploadRepo.Renew(ctx, session.ID, expiresAt); err != nil {
+			return nil, fmt.Errorf("failed to renew upload session: %w", err)
+		}
+		return session, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to get upload session: %w", err)
+	}
+
+	session = &models.UploadSession{
+		DeviceID:    deviceID,
+		ObjectKey:   key,
+		ContentType: contentType,
+		Status:      models.UploadSessionPending,
+		ExpiresAt:   expiresAt,
+	}
+	if err := s.uploadRepo.Create(ctx, session); err != nil {
+		return nil, fmt.Errorf("failed to create upload session: %w", err)
+	}
+	return session, nil
+}
+
+// readObject downloads a stored image
+func (s *trashServiceImpl) readObject(ctx context.Context, key string) ([]byte, error) {
+	reader, err := s.storageAdapter.GetObject(ctx, key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	defer reader.Close()
+
+	// The object may have changed since it was stat'ed, so the limit is applied again
+	limited := io.Reader(reader)
+	if s.config.MaxUploadBytes > 0 {
+		limited = io.LimitReader(reader, s.config.MaxUploadBytes+1)
+	}
+	data, err := io.ReadAll(limited)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if s.config.MaxUploadBytes > 0 && int64(len(data)) > s.config.MaxUploadBytes {
+		return nil, fmt.Errorf("%w: image must not exceed %d bytes", services.ErrInvalidImage, s.config.MaxUploadBytes)
+	}
+	return data, nil
+}
+
+// applyImageMetadata copies EXIF capture time, orientation and GPS onto the record,
+// flags GPS that disagrees with the submitted coordinates, and returns the image
+// bytes to store (without metadata when stripping is enabled)
+func (s *trashServiceImpl) applyImageMetadata(trash *models.TrashRecord, data []byte, contentType string) ([]byte, error) {
+	meta, err := imaging.ExtractMetadata(data, contentType)
+	if err != nil {
+		// Broken EXIF should not cost the device its submission
+		log.Printf("[EXIF] Failed to read metadata of %s: %v", trash.ImageKey, err)
+	}
+
+	trash.CapturedAt = meta.CapturedAt
+	trash.ImageOrientation = meta.Orientation
+	if meta.Latitude != nil && meta.Longitude != nil {
+		distance := utils.DistanceMeters(trash.Latitude, trash.Longitude, *meta.Latitude, *meta.Longitude)
+		trash.ExifLatitude = meta.Latitude
+		trash.ExifLongitude = meta.Longitude
+		trash.ExifDistance = &distance
+		trash.LocationMismatch = s.config.LocationMismatchMeters > 0 && distance > s.config.LocationMismatchMeters
+		if trash.LocationMismatch {
+			log.Printf("[EXIF] %s: EXIF GPS is %.0fm from submitted location", trash.ImageKey, distance)
+		}
+	}
+
+	if !s.config.StripMetadata {
+		return data, nil
+	}
+	stripped, err := imaging.StripMetadata(data, contentType, meta.Orientation)
+	if err != nil {
+		return nil, fmt.Errorf("failed to strip image metadata: %w", err)
+	}
+	return stripped, nil
+}
+
+// fingerprintImage hashes image bytes. Images that cannot be decoded only get
+// the SHA-256 and can therefore only match exact duplicates.
+func fingerprintImage(data []byte) imageFingerprint {
+	sum := sha256.Sum256(data)
+	fingerprint := imageFingerprint{sha256: hex.EncodeToString(sum[:])}
+
+	if img, err := imaging.Decode(bytes.NewReader(data)); err == nil {
+		phash := int64(imaging.DifferenceHash(img))
+		fingerprint.phash = &phash
+	}
+	return fingerprint
+}
+
+// claimedUploadResponse returns the record an upload session was claimed for by another submission
+func (s *trashServiceImpl) claimedUploadResponse(ctx context.Context, deviceID, uploadID string) (*dto.TrashResponse, error) {
+	session, err := s.findUploadSession(ctx, deviceID, uploadID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != models.UploadSessionUsed || session.TrashRecordID == nil {
+		return nil, services.ErrUploadAlreadyUsed
+	}
+	return s.duplicateOf(ctx, *session.TrashRecordID)
+}
+
+// findDuplicate returns the device's recent record with the same image content,
+// preferring an exact match over a perceptually similar one
+func (s *trashServiceImpl) findDuplicate(ctx context.Context, deviceID string, fingerprint imageFingerprint) (*models.TrashRecord, error) {
+	if !s.config.DedupEnabled {
+		return nil, nil
+	}
+
+	recent, err := s.trashRepo.FindRecentByDevice(ctx, deviceID, time.Now().Add(-s.config.DedupWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for duplicates: %w", err)
+	}
+
+	for i := range recent {
+		if recent[i].ImageSHA256 == fingerprint.sha256 {
+			return &recent[i], nil
+		}
+	}
+
+	if fingerprint.phash == nil || s.config.DedupPHashThreshold < 0 {
+		return nil, nil
+	}
+	for i := range recent {
+		if recent[i].ImagePHash == nil {
+			continue
+		}
+		distance := imaging.HammingDistance(uint64(*recent[i].ImagePHash), uint64(*fingerprint.phash))
+		if distance <= s.config.DedupPHashThreshold {
+			return &recent[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// duplicateOf returns the record an upload session already created
+func (s *trashServiceImpl) duplicateOf(ctx context.Context, id uuid.UUID) (*dto.TrashResponse, error) {
+	trash, err := s.trashRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, services.ErrUploadAlreadyUsed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash record: %w", err)
+	}
+	return s.toDuplicateResponse(ctx, trash)
+}
+
+// toDuplicateResponse returns an existing record flagged as a duplicate submission
+func (s *trashServiceImpl) toDuplicateResponse(ctx context.Context, trash *models.TrashRecord) (*dto.TrashResponse, error) {
+	response, err := s.toTrashResponse(ctx, trash)
+	if err != nil {
+		return nil, err
+	}
+	response.Duplicate = true
+	return &response, nil
+}
+
+// verifyUploadedImage checks that imageURL points into our storage, under the
+// device's own trash/{device_id}/ prefix, and that the object exists
+func (s *trashServiceImpl) verifyUploadedImage(ctx context.Context, deviceID, imageURL string) (*ports.ObjectInfo, error) {
+	prefix := s.storageAdapter.GeneratePublicURL("")
+	if !strings.HasPrefix(imageURL, prefix) {
+		return nil, fmt.Errorf("%w: image_url does not point to our storage", services.ErrInvalidImage)
+	}
+
+	key := strings.TrimPrefix(imageURL, prefix)
+	if path.Clean(key) != key || !strings.HasPrefix(key, fmt.Sprintf("trash/%s/", deviceID)) {
+		return nil, fmt.Errorf("%w: image does not belong to device %s", services.ErrInvalidImage, deviceID)
+	}
+
+	object, err := s.storageAdapter.StatObject(ctx, key)
+	if errors.Is(err, ports.ErrObjectNotFound) {
+		return nil, fmt.Errorf("%w: image has not been uploaded", services.ErrInvalidImage)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify image: %w", err)
+	}
+	// Checked before the image is read into memory
+	if s.config.MaxUploadBytes > 0 && object.Size > s.config.MaxUploadBytes {
+		return nil, fmt.Errorf("%w: image must not exceed %d bytes", services.ErrInvalidImage, s.config.MaxUploadBytes)
+	}
+
+	return object, nil
 }
 
 // GetTrashByID retrieves a trash record by its ID
@@ -122,24 +670,128 @@ func (s *trashServiceImpl) GetTrashByID(ctx context.Context, id uuid.UUID) (*dto
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trash record: %w", err)
 	}
+	if trash.Classifications, err = s.trashRepo.FindClassifications(ctx, trash.ID); err != nil {
+		return nil, fmt.Errorf("failed to get classification history: %w", err)
+	}
+	if trash.Reviews, err = s.reviewRepo.FindByTrashID(ctx, trash.ID); err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
 
-	return &dto.TrashResponse{
-		ID:            trash.ID,
-		DeviceID:     trash.DeviceID,
-		ImageURL:     trash.ImageURL,
-		Latitude:     trash.Latitude,
-		Longitude:    trash.Longitude,
-		Category:     trash.Category,
-		SubCategory:  trash.SubCategory,
-		Confidence:   trash.Confidence,
-		BinNumber:    trash.BinNumber,
-		BinLabel:     trash.BinLabel,
-		ClassifyError: trash.ClassifyError,
-		ClassifiedAt: trash.ClassifiedAt,
-		CreatedAt:    trash.CreatedAt,
+	response, err := s.toTrashResponse(ctx, trash)
+	if err != nil {
+		return nil, err
+	}
+	return &response, nil
+}
+
+// ReclassifyTrashRecord runs AI classification on an existing record again,
+// e.g. after a model upgrade or an AI outage
+func (s *trashServiceImpl) ReclassifyTrashRecord(ctx context.Context, id uuid.UUID) (*dto.TrashResponse, error) {
+	if s.classifier == nil {
+		return nil, fmt.Errorf("%w: no classifier configured", services.ErrClassificationFailed)
+	}
+
+	trash, err := s.trashRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, services.ErrTrashNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash record: %w", err)
+	}
+
+	result, err := s.classifier.Reclassify(ctx, trash)
+	if err != nil {
+		return nil, err
+	}
+	if trash.Classifications, err = s.trashRepo.FindClassifications(ctx, trash.ID); err != nil {
+		return nil, fmt.Errorf("failed to get classification history: %w", err)
+	}
+
+	response, err := s.toTrashResponse(ctx, trash)
+	if err != nil {
+		return nil, err
+	}
+	applyClassificationResult(&response, result)
+	return &response, nil
+}
+
+// ListPendingReviews retrieves the records waiting for review, oldest first
+func (s *trashServiceImpl) ListPendingReviews(ctx context.Context, req *dto.ListReviewsRequest) (*dto.ListTrashResponse, error) {
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	trashList, total, err := s.reviewRepo.FindPending(ctx, repositories.ReviewFilter{
+		DeviceID: req.DeviceID,
+		Reason:   req.Reason,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list review queue: %w", err)
+	}
+
+	data := make([]dto.TrashResponse, len(trashList))
+	for i, trash := range trashList {
+		data[i], err = s.toTrashResponse(ctx, &trash)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &dto.ListTrashResponse{
+		Data: data,
+		Pagination: dto.Pagination{
+			Total:  total,
+			Limit:  req.Limit,
+			Offset: req.Offset,
+		},
 	}, nil
 }
 
+// SubmitReview sets an operator's label on a record and takes it out of the
+// review queue. Any record can be reviewed, whether it was queued or not.
+func (s *trashServiceImpl) SubmitReview(ctx context.Context, id uuid.UUID, req *dto.SubmitReviewRequest) (*dto.TrashResponse, error) {
+	trash, err := s.trashRepo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, services.ErrTrashNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get trash record: %w", err)
+	}
+
+	review := &models.ClassificationReview{
+		TrashRecordID:       trash.ID,
+		PreviousCategory:    trash.Category,
+		PreviousSubCategory: trash.SubCategory,
+		PreviousBinNumber:   trash.BinNumber,
+		PreviousConfidence:  trash.Confidence,
+		Category:            req.Category,
+		SubCategory:         req.SubCategory,
+		BinNumber:           req.BinNumber,
+		BinLabel:            req.BinLabel,
+		Reviewer:            req.Reviewer,
+		Note:                req.Note,
+	}
+	if trash.ReviewStatus == models.ReviewPending {
+		review.Reason = trash.ReviewReason
+	}
+
+	trash.Category = req.Category
+	trash.SubCategory = req.SubCategory
+	trash.BinNumber = req.BinNumber
+	trash.BinLabel = req.BinLabel
+	trash.ReviewStatus = models.ReviewReviewed
+	// The reason the record was queued now lives on the review only
+	trash.ReviewReason = ""
+	if err := s.reviewRepo.Submit(ctx, trash, review); err != nil {
+		return nil, fmt.Errorf("failed to save review: %w", err)
+	}
+	log.Printf("[Review] Record %s labelled %s (bin %d) by %s, was %s", trash.ID, review.Category, review.BinNumber, review.Reviewer, review.PreviousCategory)
+
+	return s.GetTrashByID(ctx, trash.ID)
+}
+
 // ListTrash retrieves a list of trash records with pagination
 func (s *trashServiceImpl) ListTrash(ctx context.Context, req *dto.ListTrashRequest) (*dto.ListTrashResponse, error) {
 	// Set default values
@@ -161,20 +813,9 @@ func (s *trashServiceImpl) ListTrash(ctx context.Context, req *dto.ListTrashRequ
 	// Convert to response DTOs
 	data := make([]dto.TrashResponse, len(trashList))
 	for i, trash := range trashList {
-		data[i] = dto.TrashResponse{
-			ID:            trash.ID,
-			DeviceID:     trash.DeviceID,
-			ImageURL:     trash.ImageURL,
-			Latitude:     trash.Latitude,
-			Longitude:    trash.Longitude,
-			Category:     trash.Category,
-			SubCategory:  trash.SubCategory,
-			Confidence:   trash.Confidence,
-			BinNumber:    trash.BinNumber,
-			BinLabel:     trash.BinLabel,
-			ClassifyError: trash.ClassifyError,
-			ClassifiedAt: trash.ClassifiedAt,
-			CreatedAt:    trash.CreatedAt,
+		data[i], err = s.toTrashResponse(ctx, &trash)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -187,3 +828,202 @@ func (s *trashServiceImpl) ListTrash(ctx context.Context, req *dto.ListTrashRequ
 		},
 	}, nil
 }
+
+// toTrashResponse converts a trash record to its API representation,
+// minting signed read URLs when the bucket is private
+func (s *trashServiceImpl) toTrashResponse(ctx context.Context, trash *models.TrashRecord) (dto.TrashResponse, error) {
+	response := dto.TrashResponse{
+		ID:            trash.ID,
+		DeviceID:      trash.DeviceID,
+		ImageURL:      trash.ImageURL,
+		Latitude:      trash.Latitude,
+		Longitude:     trash.Longitude,
+		Category:      trash.Category,
+		SubCategory:   trash.SubCategory,
+		Confidence:    trash.Confidence,
+		BinNumber:     trash.BinNumber,
+		BinLabel:      trash.BinLabel,
+		ClassifyError: trash.ClassifyError,
+		ClassifiedAt:  trash.ClassifiedAt,
+
+		ClassificationStatus: string(trash.ClassificationStatus),
+		CreatedAt:            trash.CreatedAt,
+
+		CapturedAt:       trash.CapturedAt,
+		ExifLatitude:     trash.ExifLatitude,
+		ExifLongitude:    trash.ExifLongitude,
+		LocationMismatch: trash.LocationMismatch,
+
+		ReviewStatus: string(trash.ReviewStatus),
+		ReviewReason: trash.ReviewReason,
+	}
+
+	// L0 detection and model are only kept in the history; its latest entry is the one on the record
+	if n := len(trash.Classifications); n > 0 {
+		latest := trash.Classifications[n-1]
+		response.L0Detected = latest.L0Detected
+		response.L0Label = latest.L0Label
+		response.L0Confidence = latest.L0Confidence
+		response.ModelVersion = latest.ModelVersion
+		response.Detections = toDetectionEntries(latest.Detections)
+		response.Classifications = make([]dto.ClassificationEntry, n)
+		for i, c := range trash.Classifications {
+			response.Classifications[i] = dto.ClassificationEntry{
+				ID:             c.ID,
+				Category:       c.Category,
+				SubCategory:    c.SubCategory,
+				Confidence:     c.Confidence,
+				BinNumber:      c.BinNumber,
+				BinLabel:       c.BinLabel,
+				AIBinNumber:    c.AIBinNumber,
+				AIBinLabel:     c.AIBinLabel,
+				BinRuleVersion: c.BinRuleVersion,
+				L0Detected:     c.L0Detected,
+				L0Label:        c.L0Label,
+				L0Confidence:   c.L0Confidence,
+				Detections:     toDetectionEntries(c.Detections),
+				ModelName:      c.ModelName,
+				ModelVersion:   c.ModelVersion,
+				Backend:        c.Backend,
+				LatencyMs:      c.LatencyMs,
+				CreatedAt:      c.CreatedAt,
+			}
+		}
+	}
+	for _, r := range trash.Reviews {
+		response.Reviews = append(response.Reviews, dto.ReviewEntry{
+			ID:                r.ID,
+			Reason:            r.Reason,
+			PreviousCategory:  r.PreviousCategory,
+			PreviousBinNumber: r.PreviousBinNumber,
+			Category:          r.Category,
+			SubCategory:       r.SubCategory,
+			BinNumber:         r.BinNumber,
+			BinLabel:          r.BinLabel,
+			Reviewer:          r.Reviewer,
+			Note:              r.Note,
+			ReviewedAt:        r.CreatedAt,
+		})
+	}
+
+	// Legacy records without a key keep whatever URL they were created with
+	var err error
+	if trash.ImageKey != "" {
+		if response.ImageURL, err = s.readableURL(ctx, trash.ImageKey); err != nil {
+			return response, err
+		}
+	}
+	if trash.ThumbnailKey != "" {
+		if response.ThumbnailURL, err = s.readableURL(ctx, trash.ThumbnailKey); err != nil {
+			return response, err
+		}
+	}
+	if trash.MediumKey != "" {
+		if response.MediumURL, err = s.readableURL(ctx, trash.MediumKey); err != nil {
+			return response, err
+		}
+	}
+	return response, nil
+}
+
+// toDetectionEntries converts stored detections to their API representation
+func toDetectionEntries(detections []models.Detection) []dto.DetectionEntry {
+	if len(detections) == 0 {
+		return nil
+	}
+	entries := make([]dto.DetectionEntry, len(detections))
+	for i, d := range detections {
+		entries[i] = dto.DetectionEntry{
+			Label:      d.Label,
+			Confidence: d.Confidence,
+			BBox: dto.BoundingBox{
+				X:      d.BoxX,
+				Y:      d.BoxY,
+				Width:  d.BoxWidth,
+				Height: d.BoxHeight,
+			},
+			Category:           d.Category,
+			SubCategory:        d.SubCategory,
+			CategoryConfidence: d.CategoryConfidence,
+			BinNumber:          d.BinNumber,
+			BinLabel:           d.BinLabel,
+		}
+	}
+	return entries
+}
+
+// storedImageURL returns the URL persisted on a record: the public URL, or
+// nothing in private mode where only the object key is kept
+func (s *trashServiceImpl) storedImageURL(key string) string {
+	if s.config.PrivateBucket {
+		return ""
+	}
+	return s.storageAdapter.GeneratePublicURL(key)
+}
+
+// readableURL returns a URL clients can fetch an object from: a short-lived
+// signed URL in private mode, otherwise the public URL
+func (s *trashServiceImpl) readableURL(ctx context.Context, key string) (string, error) {
+	if !s.config.PrivateBucket {
+		return s.storageAdapter.GeneratePublicURL(key), nil
+	}
+	signedURL, err := s.storageAdapter.GeneratePresignedDownloadURL(ctx, key, s.config.SignedURLTTL)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign image URL: %w", err)
+	}
+	return signedURL, nil
+}
+
+// generateVariants creates the thumbnail and medium JPEGs for a stored image
+// and records their keys. Runs in the background, so failures are only logged.
+func (s *trashServiceImpl) generateVariants(id uuid.UUID, imageKey string) {
+	if imageKey == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), variantTimeout)
+	defer cancel()
+
+	reader, err := s.storageAdapter.GetObject(ctx, imageKey)
+	if err != nil {
+		log.Printf("[Variants] Failed to read %s: %v", imageKey, err)
+		return
+	}
+	img, err := imaging.Decode(reader)
+	reader.Close()
+	if err != nil {
+		log.Printf("[Variants] Failed to decode %s: %v", imageKey, err)
+		return
+	}
+
+	keys := make(map[string]string, 2)
+	for variant, size := range map[string]int{
+		variantThumbnail: s.config.ThumbnailSize,
+		variantMedium:    s.config.MediumSize,
+	} {
+		data, err := imaging.EncodeJPEG(imaging.Fit(img, size), variantQuality)
+		if err != nil {
+			log.Printf("[Variants] Failed to encode %s for %s: %v", variant, imageKey, err)
+			return
+		}
+
+		key := variantKey(imageKey, variant)
+		if err := s.storageAdapter.PutObject(ctx, key, bytes.NewReader(data), "image/jpeg"); err != nil {
+			log.Printf("[Variants] Failed to store %s: %v", key, err)
+			return
+		}
+		keys[variant] = key
+	}
+
+	if err := s.trashRepo.UpdateImageVariants(ctx, id, keys[variantThumbnail], keys[variantMedium]); err != nil {
+		log.Printf("[Variants] Failed to update record %s: %v", id, err)
+	}
+}
+
+// variantKey derives the storage key of a resized variant, e.g.
+// trash/DEV1/123.png -> variants/thumbnail/trash/DEV1/123.jpg.
+// Variants live outside trash/ so the orphan collector never sees them.
+func variantKey(imageKey, variant string) string {
+	base := strings.TrimSuffix(imageKey, path.Ext(imageKey))
+	return fmt.Sprintf("variants/%s/%s.jpg", variant, base)
+}
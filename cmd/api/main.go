@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"gofiber-smart-trash/interfaces/api/handlers"
 	"gofiber-smart-trash/interfaces/api/routes"
@@ -22,19 +23,23 @@ func main() {
 		log.Fatal("Failed to initialize container:", err)
 	}
 
-	// Setup graceful shutdown
-	setupGracefulShutdown(container)
+	// Start background jobs (storage GC, etc.)
+	container.StartBackgroundJobs()
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
-		AppName: container.GetConfig().App.Name,
+		AppName:   container.GetConfig().App.Name,
+		BodyLimit: bodyLimit(container.GetConfig().Storage.MaxUploadBytes),
 	})
 
+	// Setup graceful shutdown
+	setupGracefulShutdown(app)
+
 	// Create handlers
-	h := handlers.NewHandlers(container.GetTrashService())
+	h := handlers.NewHandlers(container.GetTrashService(), container.GetLocalFileStore(), container.GetAICircuit(), container.GetDatasetExportService(), container.GetShadowService(), container.GetBinMappingService())
 
 	// Setup routes (routes include middleware setup)
-	routes.SetupRoutes(app, h)
+	routes.SetupRoutes(app, h, container.GetConfig().Admin.APIKey)
 
 	// Start server
 	port := container.GetConfig().App.Port
@@ -44,13 +49,36 @@ func main() {
 	log.Printf("📖 API endpoints:")
 	log.Printf("   GET  /api/upload-url")
 	log.Printf("   POST /api/trash")
+	log.Printf("   POST /api/trash/upload")
 	log.Printf("   GET  /api/trash")
 	log.Printf("   GET  /api/trash/:id")
+	if container.GetLocalFileStore() != nil {
+		log.Printf("   PUT  /files/upload/*")
+		log.Printf("   GET  /files/*")
+	}
 
-	log.Fatal(app.Listen(":" + port))
+	if err := app.Listen(":" + port); err != nil {
+		log.Fatal(err)
+	}
+
+	// Listen returns once the server was shut down
+	if err := container.Cleanup(); err != nil {
+		log.Printf("❌ Error during cleanup: %v", err)
+	}
+
+	log.Println("👋 Shutdown complete")
+}
+
+// bodyLimit leaves room for multipart overhead on top of the maximum image size
+func bodyLimit(maxUploadBytes int64) int {
+	if maxUploadBytes <= 0 {
+		return fiber.DefaultBodyLimit
+	}
+	return int(maxUploadBytes) + 1024*1024
 }
 
-func setupGracefulShutdown(container *di.Container) {
+// setupGracefulShutdown stops the server on SIGINT/SIGTERM; main cleans up once Listen returns
+func setupGracefulShutdown(app *fiber.App) {
 	c := make(chan os.Signal, 1)
 	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
 
@@ -58,11 +86,9 @@ func setupGracefulShutdown(container *di.Container) {
 		<-c
 		log.Println("\n🛑 Gracefully shutting down...")
 
-		if err := container.Cleanup(); err != nil {
-			log.Printf("❌ Error during cleanup: %v", err)
+		// Cancels the context of streaming responses such as dataset exports
+		if err := app.ShutdownWithTimeout(10 * time.Second); err != nil {
+			log.Printf("❌ Error stopping server: %v", err)
 		}
-
-		log.Println("👋 Shutdown complete")
-		os.Exit(0)
 	}()
-}
\ No newline at end of file
+}
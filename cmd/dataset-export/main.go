@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"gofiber-smart-trash/application/services"
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/pkg/di"
+	"gofiber-smart-trash/pkg/utils"
+)
+
+// dataset-export writes labelled trash images as a training dataset: an
+// ImageFolder tree for the L1 classifier and YOLO/COCO labels for the L0
+// detector, split deterministically into train, val and test.
+//
+//	go run ./cmd/dataset-export -out=dataset.zip -review-status=reviewed
+//	go run ./cmd/dataset-export -from=2024-06-01 -min-confidence=0.8 -formats=imagefolder
+func main() {
+	out := flag.String("out", "dataset.zip", "archive to write")
+	from := flag.String("from", "", "only records created on or after this date (2006-01-02 or RFC 3339)")
+	to := flag.String("to", "", "only records created before this date (2006-01-02 or RFC 3339)")
+	minConfidence := flag.Float64("min-confidence", 0, "skip AI labels below this confidence (reviewed records are always kept)")
+	reviewStatus := flag.String("review-status", "", "comma-separated review statuses to include: reviewed, pending, none")
+	formats := flag.String("formats", "", "comma-separated formats: imagefolder, yolo, coco (default imagefolder)")
+	train := flag.Float64("train", 0, "fraction of images in the train split (default 0.8)")
+	val := flag.Float64("val", 0, "fraction of images in the val split (default 0.1)")
+	seed := flag.String("seed", "", "split seed; keep it to reproduce the same split (default smart-trash)")
+	batchSize := flag.Int("batch", 100, "records loaded per batch")
+	flag.Parse()
+
+	opts := dto.DatasetExportOptions{
+		MinConfidence:  *minConfidence,
+		ReviewStatuses: utils.SplitList(*reviewStatus),
+		Formats:        utils.SplitList(*formats),
+		TrainRatio:     *train,
+		ValRatio:       *val,
+		Seed:           *seed,
+		BatchSize:      *batchSize,
+	}
+	var err error
+	if opts.CreatedFrom, err = utils.ParseDate(*from); err != nil {
+		log.Fatal("Invalid -from:", err)
+	}
+	if opts.CreatedTo, err = utils.ParseDate(*to); err != nil {
+		log.Fatal("Invalid -to:", err)
+	}
+
+	container := di.NewContainer()
+	if err := container.Initialize(); err != nil {
+		log.Fatal("Failed to initialize container:", err)
+	}
+	defer container.Cleanup()
+
+	exporter := services.NewDatasetExportService(container.TrashRepo, container.StorageAdapter)
+	if err := exporter.Validate(&opts); err != nil {
+		log.Printf("❌ %v", err)
+		container.Cleanup()
+		os.Exit(1)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Write next to the destination and rename, so a failed run leaves no partial archive
+	tmp, err := os.CreateTemp(filepath.Dir(*out), ".dataset-*.zip")
+	if err != nil {
+		log.Printf("❌ Failed to create archive: %v", err)
+		container.Cleanup()
+		os.Exit(1)
+	}
+	defer os.Remove(tmp.Name())
+
+	report, err := exporter.Export(ctx, opts, tmp)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), *out)
+	}
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	}
+	if err != nil {
+		log.Printf("❌ %v", err)
+		os.Remove(tmp.Name())
+		container.Cleanup()
+		os.Exit(1)
+	}
+
+	log.Printf("✓ Wrote %d images to %s", report.Exported, *out)
+	if report.Failed > 0 {
+		log.Printf("⚠️  %d records could not be exported (see failures above)", report.Failed)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"gofiber-smart-trash/application/services"
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/pkg/di"
+	"gofiber-smart-trash/pkg/utils"
+
+	"github.com/google/uuid"
+)
+
+// reclassify runs AI classification again over existing trash records, e.g.
+// after a model upgrade or to repair records saved while the AI service was down.
+//
+//	go run ./cmd/reclassify -empty-category -failed
+//	go run ./cmd/reclassify -from=2024-06-01 -to=2024-07-01 -device=esp32-001 -concurrency=8
+func main() {
+	from := flag.String("from", "", "only records created on or after this date (2006-01-02 or RFC 3339)")
+	to := flag.String("to", "", "only records created before this date (2006-01-02 or RFC 3339)")
+	deviceID := flag.String("device", "", "only records from this device")
+	emptyCategory := flag.Bool("empty-category", false, "only records without a category")
+	failed := flag.Bool("failed", false, "only records whose last classification failed (with -empty-category: either)")
+	all := flag.Bool("all", false, "reclassify every record when no other filter is given")
+	concurrency := flag.Int("concurrency", 4, "parallel calls to the AI service")
+	batchSize := flag.Int("batch", 100, "records loaded per batch")
+	after := flag.String("after", "", "resume after this record ID (last_id of an interrupted run)")
+	dryRun := flag.Bool("dry-run", false, "count matching records without calling the AI service")
+	flag.Parse()
+
+	opts := dto.ReclassifyOptions{
+		DeviceID:      *deviceID,
+		EmptyCategory: *emptyCategory,
+		HasError:      *failed,
+		BatchSize:     *batchSize,
+		Concurrency:   *concurrency,
+		DryRun:        *dryRun,
+	}
+	var err error
+	if opts.CreatedFrom, err = utils.ParseDate(*from); err != nil {
+		log.Fatal("Invalid -from:", err)
+	}
+	if opts.CreatedTo, err = utils.ParseDate(*to); err != nil {
+		log.Fatal("Invalid -to:", err)
+	}
+	if *after != "" {
+		if opts.AfterID, err = uuid.Parse(*after); err != nil {
+			log.Fatal("Invalid -after:", err)
+		}
+	}
+	if opts.CreatedFrom == nil && opts.CreatedTo == nil && opts.DeviceID == "" && !opts.EmptyCategory && !opts.HasError && !*all {
+		log.Fatal("No filter given; pass -all to reclassify every record")
+	}
+
+	container := di.NewContainer()
+	if err := container.Initialize(); err != nil {
+		log.Fatal("Failed to initialize container:", err)
+	}
+	defer container.Cleanup()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	started := time.Now()
+	opts.OnProgress = func(report *dto.ReclassifyReport) {
+		if report.Processed == 0 || report.Matched == 0 {
+			return
+		}
+		elapsed := time.Since(started)
+		remaining := time.Duration(float64(elapsed) / float64(report.Processed) * float64(report.Matched-int64(report.Processed)))
+		log.Printf("[Reclassify] %.1f%% done, about %s left", float64(report.Processed)/float64(report.Matched)*100, max(remaining, 0).Round(time.Second))
+	}
+
+	reclassification := services.NewReclassificationService(container.TrashRepo, container.ClassificationService)
+	report, err := reclassification.Reclassify(ctx, opts)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	}
+	if err != nil {
+		log.Printf("❌ %v", err)
+		if report != nil && report.LastID != uuid.Nil {
+			log.Printf("Resume with -after=%s", report.LastID)
+		}
+		container.Cleanup()
+		os.Exit(1)
+	}
+	if report.Failed > 0 {
+		log.Printf("⚠️  %d records failed and kept their previous classification (see failures above)", report.Failed)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/pkg/di"
+)
+
+// storage-gc removes images under trash/ that have no trash record.
+//
+//	go run ./cmd/storage-gc -dry-run
+//	go run ./cmd/storage-gc -mode=delete -grace=72h
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report orphaned images without modifying storage")
+	mode := flag.String("mode", "", "delete or quarantine (default: STORAGE_GC_MODE)")
+	grace := flag.Duration("grace", 0, "minimum image age before it is collected (default: STORAGE_GC_GRACE_PERIOD)")
+	prefix := flag.String("prefix", "trash/", "object prefix to scan")
+	flag.Parse()
+
+	container := di.NewContainer()
+	if err := container.Initialize(); err != nil {
+		log.Fatal("Failed to initialize container:", err)
+	}
+	defer container.Cleanup()
+
+	cfg := container.GetConfig().StorageGC
+	opts := dto.StorageGCOptions{
+		Prefix:      *prefix,
+		GracePeriod: cfg.GracePeriod,
+		Mode:        cfg.Mode,
+		DryRun:      *dryRun || cfg.DryRun,
+	}
+	if *mode != "" {
+		opts.Mode = *mode
+	}
+	if *grace > 0 {
+		opts.GracePeriod = *grace
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	report, err := container.GetStorageGCService().CollectOrphans(ctx, opts)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	}
+	if err != nil {
+		log.Printf("❌ %v", err)
+		container.Cleanup()
+		os.Exit(1)
+	}
+}
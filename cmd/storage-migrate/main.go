@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"gofiber-smart-trash/application/services"
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/pkg/config"
+	"gofiber-smart-trash/pkg/di"
+
+	"github.com/google/uuid"
+)
+
+// targetEnvPrefix prefixes the storage env vars that configure the destination,
+// e.g. MIGRATE_TARGET_STORAGE_PROVIDER=s3, MIGRATE_TARGET_R2_BUCKET=...
+const targetEnvPrefix = "MIGRATE_TARGET_"
+
+// migrationState is persisted after every batch so an interrupted run can resume
+type migrationState struct {
+	LastID    uuid.UUID `json:"last_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// storage-migrate copies every image referenced by trash_records from the
+// configured storage provider to the one configured with MIGRATE_TARGET_* vars,
+// verifies each copy and rewrites image_url.
+//
+//	MIGRATE_TARGET_STORAGE_PROVIDER=s3 MIGRATE_TARGET_R2_BUCKET=trash go run ./cmd/storage-migrate -dry-run
+//	go run ./cmd/storage-migrate -batch=200
+func main() {
+	dryRun := flag.Bool("dry-run", false, "report what would be copied without writing anything")
+	batchSize := flag.Int("batch", 100, "records copied and rewritten per batch")
+	statePath := flag.String("state", "storage-migrate.state.json", "file used to resume an interrupted migration")
+	restart := flag.Bool("restart", false, "ignore the state file and start from the first record")
+	flag.Parse()
+
+	if os.Getenv(targetEnvPrefix+"STORAGE_PROVIDER") == "" {
+		log.Fatalf("%sSTORAGE_PROVIDER must be set to the destination provider", targetEnvPrefix)
+	}
+
+	container := di.NewContainer()
+	if err := container.Initialize(); err != nil {
+		log.Fatal("Failed to initialize container:", err)
+	}
+	defer container.Cleanup()
+
+	sourceCfg := container.GetConfig().Storage
+	targetCfg := config.LoadStorageConfig(targetEnvPrefix)
+	if targetCfg.Provider == sourceCfg.Provider && targetCfg.Bucket == sourceCfg.Bucket &&
+		targetCfg.Endpoint == sourceCfg.Endpoint && targetCfg.LocalPath == sourceCfg.LocalPath {
+		log.Fatal("Source and target storage are the same")
+	}
+	target, err := di.NewStorageAdapter(targetCfg)
+	if err != nil {
+		log.Fatal("Failed to initialize target storage:", err)
+	}
+	if closer, ok := target.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	opts := dto.StorageMigrationOptions{
+		BatchSize: *batchSize,
+		DryRun:    *dryRun,
+	}
+	if !*restart {
+		state, err := loadState(*statePath)
+		if err != nil {
+			log.Fatal("Failed to read state file:", err)
+		}
+		opts.AfterID = state.LastID
+		if state.LastID != uuid.Nil {
+			log.Printf("✓ Resuming after record %s", state.LastID)
+		}
+	}
+	// A dry run must not move the cursor of the real migration
+	if !*dryRun {
+		opts.OnBatch = func(report *dto.StorageMigrationReport) error {
+			return saveState(*statePath, migrationState{LastID: report.LastID, UpdatedAt: time.Now()})
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	migration := services.NewStorageMigrationService(container.TrashRepo, container.StorageAdapter, target)
+	report, err := migration.Migrate(ctx, opts)
+	if report != nil {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(report)
+	}
+	if err != nil {
+		log.Printf("❌ %v", err)
+		container.Cleanup()
+		os.Exit(1)
+	}
+	if report.Failed > 0 {
+		log.Printf("⚠️  %d records failed; fix the cause and re-run with -restart (copied objects are skipped)", report.Failed)
+	}
+}
+
+// loadState reads the resume cursor, returning an empty state if there is none
+func loadState(path string) (migrationState, error) {
+	var state migrationState
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	return state, json.Unmarshal(data, &state)
+}
+
+// saveState writes the resume cursor atomically so a crash never leaves a torn file
+func saveState(path string, state migrationState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".storage-migrate-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
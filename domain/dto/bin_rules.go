@@ -0,0 +1,33 @@
+package dto
+
+import "time"
+
+// BinRuleEntry is one rule of a rule set. Empty conditions match anything.
+type BinRuleEntry struct {
+	Category      string  `json:"category,omitempty" validate:"max=50"`
+	SubCategory   string  `json:"sub_category,omitempty" validate:"max=50"`
+	L0Label       string  `json:"l0_label,omitempty" validate:"max=50"`
+	MinConfidence float64 `json:"min_confidence,omitempty" validate:"min=0,max=1"` // L1 confidence
+
+	BinNumber int    `json:"bin_number" validate:"required,min=1,max=6"`
+	BinLabel  string `json:"bin_label" validate:"required,max=50"`
+}
+
+// CreateBinRuleSetRequest represents the request body of POST /api/admin/bin-rules
+type CreateBinRuleSetRequest struct {
+	Rules     []BinRuleEntry `json:"rules" validate:"required,min=1,dive"` // Evaluated in order, the first match wins
+	Note      string         `json:"note"`
+	CreatedBy string         `json:"created_by" validate:"required,max=100"`
+	Activate  *bool          `json:"activate"` // Defaults to true
+}
+
+// BinRuleSetResponse is one version of the bin rules
+type BinRuleSetResponse struct {
+	Version     int            `json:"version"`
+	Active      bool           `json:"active"`
+	Note        string         `json:"note,omitempty"`
+	CreatedBy   string         `json:"created_by"`
+	CreatedAt   time.Time      `json:"created_at"`
+	ActivatedAt *time.Time     `json:"activated_at,omitempty"`
+	Rules       []BinRuleEntry `json:"rules,omitempty"` // Omitted when listing versions
+}
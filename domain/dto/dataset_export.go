@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Dataset export formats
+const (
+	DatasetFormatImageFolder = "imagefolder" // L1 classifier: <split>/<category>/<image>
+	DatasetFormatYOLO        = "yolo"        // L0 detector: images/, labels/ and data.yaml
+	DatasetFormatCOCO        = "coco"        // L0 detector: one instances_<split>.json per split
+)
+
+// DatasetExportRequest holds the query parameters of GET /api/admin/dataset/export
+type DatasetExportRequest struct {
+	From          string  `query:"from"` // 2006-01-02 or RFC 3339
+	To            string  `query:"to"`   // Exclusive
+	MinConfidence float64 `query:"min_confidence" validate:"min=0,max=1"`
+	ReviewStatus  string  `query:"review_status"` // Comma-separated: reviewed, pending, none
+	Formats       string  `query:"formats"`       // Comma-separated: imagefolder, yolo, coco
+	TrainRatio    float64 `query:"train" validate:"min=0,max=1"`
+	ValRatio      float64 `query:"val" validate:"min=0,max=1"`
+	Seed          string  `query:"seed"`
+}
+
+// DatasetExportOptions selects the records of a training dataset and its layout
+type DatasetExportOptions struct {
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time // Exclusive
+	MinConfidence  float64    // Applies to AI labels only; reviewed records always qualify
+	ReviewStatuses []string   // reviewed, pending, none; empty exports all
+	Formats        []string   // Defaults to imagefolder
+
+	// Records are split by hashing their image with Seed, so a record keeps its
+	// split across exports. Whatever train and val leave goes to test.
+	TrainRatio float64
+	ValRatio   float64
+	Seed       string
+
+	BatchSize int
+}
+
+// DatasetExportFailure is a record left out of the export
+type DatasetExportFailure struct {
+	RecordID uuid.UUID `json:"record_id"`
+	Error    string    `json:"error"`
+}
+
+// DatasetExportReport summarises a dataset export; it is also written to the archive as manifest.json
+type DatasetExportReport struct {
+	Formats           []string               `json:"formats"`
+	Seed              string                 `json:"seed"`
+	TrainRatio        float64                `json:"train_ratio"`
+	ValRatio          float64                `json:"val_ratio"`
+	Records           int                    `json:"records"` // Records matching the filters
+	Exported          int                    `json:"exported"`
+	Reviewed          int                    `json:"reviewed"`           // Exported records labelled by an operator
+	SkippedDuplicates int                    `json:"skipped_duplicates"` // Same image as an exported record
+	Splits            map[string]int         `json:"splits"`
+	Classes           map[string]int         `json:"classes"` // L1 category -> images
+	DetectionImages   int                    `json:"detection_images"`
+	DetectionBoxes    int                    `json:"detection_boxes"`
+	DetectionSkipped  int                    `json:"detection_skipped"` // Records without a stored bounding box
+	Failed            int                    `json:"failed"`
+	Failures          []DatasetExportFailure `json:"failures"`
+	StartedAt         time.Time              `json:"started_at"`
+	FinishedAt        time.Time              `json:"finished_at"`
+}
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReclassifyOptions selects records to classify again and controls the run.
+// Filters are combined with AND, except EmptyCategory and HasError which
+// select records matching either when both are set.
+type ReclassifyOptions struct {
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time // Exclusive
+	DeviceID      string
+	EmptyCategory bool // Records without a category
+	HasError      bool // Records whose last classification failed
+
+	AfterID     uuid.UUID // Resume after this record ID (uuid.Nil starts from the beginning)
+	BatchSize   int       // Records loaded per page
+	Concurrency int       // Parallel calls to the AI service
+	DryRun      bool      // Count matching records without calling the AI service
+
+	// OnProgress is called after each batch, e.g. to print progress or persist report.LastID
+	OnProgress func(report *ReclassifyReport)
+}
+
+// ReclassifyFailure is a record that could not be reclassified
+type ReclassifyFailure struct {
+	RecordID uuid.UUID `json:"record_id"`
+	Error    string    `json:"error"`
+}
+
+// ReclassifyReport summarises a batch reclassification run
+type ReclassifyReport struct {
+	DryRun       bool                `json:"dry_run"`
+	Matched      int64               `json:"matched"` // Records selected by the filters when the run started
+	Processed    int                 `json:"processed"`
+	Reclassified int                 `json:"reclassified"`
+	Changed      int                 `json:"changed"` // Reclassified records whose category or bin changed
+	Failed       int                 `json:"failed"`
+	Failures     []ReclassifyFailure `json:"failures"`
+	LastID       uuid.UUID           `json:"last_id"` // Resume cursor
+	StartedAt    time.Time           `json:"started_at"`
+	FinishedAt   time.Time           `json:"finished_at"`
+}
@@ -0,0 +1,34 @@
+package dto
+
+import "time"
+
+// ShadowReportRequest holds the query parameters of GET /api/admin/shadow/report
+type ShadowReportRequest struct {
+	From         string `query:"from"` // 2006-01-02 or RFC 3339
+	To           string `query:"to"`   // Exclusive
+	ModelVersion string `query:"model_version"`
+}
+
+// ShadowCategoryAgreement compares how often each model chose a category
+type ShadowCategoryAgreement struct {
+	Category      string  `json:"category"`
+	Primary       int64   `json:"primary"` // Images the production model put in this category
+	Shadow        int64   `json:"shadow"`  // Images the candidate model put in this category
+	Agreed        int64   `json:"agreed"`  // Images both models put in this category
+	AgreementRate float64 `json:"agreement_rate"`
+}
+
+// ShadowReport summarises how a candidate model classified live traffic
+// compared with the production model
+type ShadowReport struct {
+	From          *time.Time                `json:"from,omitempty"`
+	To            *time.Time                `json:"to,omitempty"`
+	ModelVersion  string                    `json:"model_version,omitempty"`
+	Compared      int64                     `json:"compared"`
+	Agreed        int64                     `json:"agreed"`
+	AgreementRate float64                   `json:"agreement_rate"`
+	Failed        int64                     `json:"failed"` // Shadow calls without a result
+	Categories    []ShadowCategoryAgreement `json:"categories"`
+	// Confusion counts images by production category, then candidate category
+	Confusion map[string]map[string]int64 `json:"confusion"`
+}
@@ -0,0 +1,39 @@
+package dto
+
+import "time"
+
+// StorageGCOptions controls an orphaned image collection run
+type StorageGCOptions struct {
+	Prefix      string        // Object prefix to scan (default: trash/)
+	GracePeriod time.Duration // Objects younger than this are never touched
+	Mode        string        // "delete" or "quarantine"
+	DryRun      bool          // Report orphans without modifying storage
+}
+
+// OrphanObject is an image with no trash record referencing it
+type OrphanObject struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	Action       string    `json:"action"` // deleted, quarantined, would_delete, would_quarantine, failed
+	Error        string    `json:"error,omitempty"`
+}
+
+// StorageGCReport summarises an orphaned image collection run
+type StorageGCReport struct {
+	DryRun          bool           `json:"dry_run"`
+	Mode            string         `json:"mode"`
+	Prefix          string         `json:"prefix"`
+	GracePeriod     string         `json:"grace_period"`
+	Scanned         int            `json:"scanned"`
+	TooRecent       int            `json:"too_recent"`
+	Referenced      int            `json:"referenced"`
+	Orphaned        int            `json:"orphaned"`
+	Removed         int            `json:"removed"`
+	Failed          int            `json:"failed"`
+	OrphanedBytes   int64          `json:"orphaned_bytes"`
+	ExpiredSessions int64          `json:"expired_sessions"`
+	Orphans         []OrphanObject `json:"orphans"`
+	StartedAt       time.Time      `json:"started_at"`
+	FinishedAt      time.Time      `json:"finished_at"`
+}
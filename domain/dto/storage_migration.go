@@ -0,0 +1,42 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StorageMigrationOptions controls a copy of referenced images to another storage provider
+type StorageMigrationOptions struct {
+	AfterID   uuid.UUID // Resume after this record ID (uuid.Nil starts from the beginning)
+	BatchSize int       // Records copied and rewritten per batch
+	DryRun    bool      // Report what would be copied without writing anything
+
+	// OnBatch is called after each batch has been copied and committed, e.g. to
+	// persist report.LastID so an interrupted run can resume
+	OnBatch func(report *StorageMigrationReport) error
+}
+
+// MigrationFailure is a record whose images could not be migrated
+type MigrationFailure struct {
+	RecordID uuid.UUID `json:"record_id"`
+	Key      string    `json:"key"`
+	Error    string    `json:"error"`
+}
+
+// StorageMigrationReport summarises a storage migration run
+type StorageMigrationReport struct {
+	DryRun          bool               `json:"dry_run"`
+	Records         int                `json:"records"`
+	Migrated        int                `json:"migrated"`         // Records whose images are all at the target
+	AlreadyMigrated int                `json:"already_migrated"` // Records already pointing at the target
+	ObjectsCopied   int                `json:"objects_copied"`
+	ObjectsSkipped  int                `json:"objects_skipped"` // Identical object already at the target
+	BytesCopied     int64              `json:"bytes_copied"`
+	URLsRewritten   int                `json:"urls_rewritten"`
+	Failed          int                `json:"failed"`
+	Failures        []MigrationFailure `json:"failures"`
+	LastID          uuid.UUID          `json:"last_id"` // Resume cursor
+	StartedAt       time.Time          `json:"started_at"`
+	FinishedAt      time.Time          `json:"finished_at"`
+}
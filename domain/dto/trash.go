@@ -8,50 +8,169 @@ import (
 
 // Request DTOs
 
+type UploadURLRequest struct {
+	DeviceID       string `query:"device_id" validate:"required"`
+	ContentType    string `query:"content_type"`    // Defaults to image/jpeg
+	ContentLength  int64  `query:"content_length"`  // Exact file size in bytes, if known
+	ContentMD5     string `query:"content_md5"`     // Base64 MD5 of the file (optional)
+	ChecksumSHA256 string `query:"checksum_sha256"` // Base64 SHA-256 of the file (optional)
+}
+
 type CreateTrashRequest struct {
 	DeviceID  string  `json:"device_id" validate:"required"`
-	ImageURL  string  `json:"image_url" validate:"required,url"`
+	UploadID  string  `json:"upload_id" validate:"required_without=ImageURL,omitempty,uuid"`
+	ImageURL  string  `json:"image_url" validate:"required_without=UploadID,omitempty,url"` // Deprecated: use upload_id
 	Latitude  float64 `json:"latitude" validate:"required"`
 	Longitude float64 `json:"longitude" validate:"required"`
 }
 
+// UploadTrashRequest holds the form fields of a multipart image upload
+type UploadTrashRequest struct {
+	DeviceID  string  `form:"device_id" json:"device_id" validate:"required"`
+	Latitude  float64 `form:"latitude" json:"latitude" validate:"required"`
+	Longitude float64 `form:"longitude" json:"longitude" validate:"required"`
+}
+
 type ListTrashRequest struct {
 	DeviceID string `query:"device_id"`
 	Limit    int    `query:"limit" validate:"min=1,max=100"`
 	Offset   int    `query:"offset" validate:"min=0"`
 }
 
+// ListReviewsRequest filters the review queue
+type ListReviewsRequest struct {
+	DeviceID string `query:"device_id"`
+	Reason   string `query:"reason" validate:"omitempty,oneof=low_confidence no_object"`
+	Limit    int    `query:"limit" validate:"min=0,max=100"`
+	Offset   int    `query:"offset" validate:"min=0"`
+}
+
+// SubmitReviewRequest is an operator's corrected label for a record
+type SubmitReviewRequest struct {
+	Category    string `json:"category" validate:"required,max=50"`
+	SubCategory string `json:"sub_category" validate:"max=50"`
+	BinNumber   int    `json:"bin_number" validate:"required,min=1,max=6"`
+	BinLabel    string `json:"bin_label" validate:"max=50"`
+	Reviewer    string `json:"reviewer" validate:"required,max=100"` // Operator name or ID
+	Note        string `json:"note"`
+}
+
 // Response DTOs
 
 type UploadURLResponse struct {
-	UploadURL string `json:"upload_url"`
-	ImageURL  string `json:"image_url"`
-	ExpiresIn int64  `json:"expires_in"`
+	UploadID      uuid.UUID         `json:"upload_id"` // Pass to POST /api/trash
+	UploadURL     string            `json:"upload_url"`
+	UploadMethod  string            `json:"upload_method"`            // PUT, or POST for form uploads
+	UploadHeaders map[string]string `json:"upload_headers,omitempty"` // Headers to send with a PUT upload
+	FormFields    map[string]string `json:"form_fields,omitempty"`    // Fields to send with a POST upload
+	ImageURL      string            `json:"image_url"`
+	MaxBytes      int64             `json:"max_bytes,omitempty"`
+	ExpiresIn     int64             `json:"expires_in"`
 }
 
 type TrashResponse struct {
-	ID        uuid.UUID `json:"id"`
-	DeviceID  string    `json:"device_id"`
-	ImageURL  string    `json:"image_url"`
-	Latitude  float64   `json:"latitude"`
-	Longitude float64   `json:"longitude"`
+	ID           uuid.UUID `json:"id"`
+	DeviceID     string    `json:"device_id"`
+	ImageURL     string    `json:"image_url"`
+	ThumbnailURL string    `json:"thumbnail_url,omitempty"` // Small variant for list views
+	MediumURL    string    `json:"medium_url,omitempty"`    // Mid-size variant for detail views
+	Latitude     float64   `json:"latitude"`
+	Longitude    float64   `json:"longitude"`
+
+	// Photo metadata taken from EXIF (the stored image has EXIF removed)
+	CapturedAt       *time.Time `json:"captured_at,omitempty"`
+	ExifLatitude     *float64   `json:"exif_latitude,omitempty"`
+	ExifLongitude    *float64   `json:"exif_longitude,omitempty"`
+	LocationMismatch bool       `json:"location_mismatch,omitempty"` // EXIF GPS is far from latitude/longitude
 
 	// Classification results (from AI)
-	Category      string    `json:"category"`
-	SubCategory   string    `json:"sub_category,omitempty"`
-	Confidence    float64   `json:"confidence"`
-	BinNumber     int       `json:"bin_number"`
-	BinLabel      string    `json:"bin_label"`
-	Message       string    `json:"message,omitempty"` // Human-readable result message
-	L0Detected    bool      `json:"l0_detected"`              // L0 พบวัตถุหรือไม่
-	L0Label       string    `json:"l0_label,omitempty"`       // YOLO detected object (bottle, cup, etc.)
-	L0Confidence  float64   `json:"l0_confidence,omitempty"`  // YOLO confidence
-	ClassifyError string    `json:"classify_error,omitempty"`
-	ClassifiedAt  time.Time `json:"classified_at,omitempty"`
+	ClassificationStatus string           `json:"classification_status"` // pending, classified, failed
+	Category             string           `json:"category"`
+	SubCategory          string           `json:"sub_category,omitempty"`
+	Confidence           float64          `json:"confidence"`
+	BinNumber            int              `json:"bin_number"`
+	BinLabel             string           `json:"bin_label"`
+	Message              string           `json:"message,omitempty"`       // Human-readable result message
+	L0Detected           bool             `json:"l0_detected"`             // L0 พบวัตถุหรือไม่
+	L0Label              string           `json:"l0_label,omitempty"`      // YOLO detected object (bottle, cup, etc.)
+	L0Confidence         float64          `json:"l0_confidence,omitempty"` // YOLO confidence
+	Detections           []DetectionEntry `json:"detections,omitempty"`    // Objects of the latest classification, most confident first
+	ClassifyError        string           `json:"classify_error,omitempty"`
+	ClassifiedAt         time.Time        `json:"classified_at,omitempty"`
+	ModelVersion         string           `json:"model_version,omitempty"` // Model of the latest classification
 
+	// Human review: pending while queued, reviewed once an operator set the label
+	ReviewStatus string `json:"review_status,omitempty"`
+	ReviewReason string `json:"review_reason,omitempty"` // low_confidence, no_object
+
+	// Classification and review history, oldest first (GET /api/trash/:id only)
+	Classifications []ClassificationEntry `json:"classifications,omitempty"`
+	Reviews         []ReviewEntry         `json:"reviews,omitempty"`
+
+	// Duplicate is set when the submission matched an existing record, which is returned instead
+	Duplicate bool      `json:"duplicate,omitempty"`
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// ClassificationEntry is one AI result in a record's classification history
+type ClassificationEntry struct {
+	ID             uuid.UUID        `json:"id"`
+	Category       string           `json:"category"`
+	SubCategory    string           `json:"sub_category,omitempty"`
+	Confidence     float64          `json:"confidence"`
+	BinNumber      int              `json:"bin_number"`
+	BinLabel       string           `json:"bin_label"`
+	AIBinNumber    int              `json:"ai_bin_number,omitempty"` // Bin suggested by the AI service
+	AIBinLabel     string           `json:"ai_bin_label,omitempty"`
+	BinRuleVersion int              `json:"bin_rule_version,omitempty"` // Bin rule set that chose the bin, absent if the AI suggestion was used
+	L0Detected     bool             `json:"l0_detected"`
+	L0Label        string           `json:"l0_label,omitempty"`
+	L0Confidence   float64          `json:"l0_confidence,omitempty"`
+	Detections     []DetectionEntry `json:"detections,omitempty"`
+	ModelName      string           `json:"model_name,omitempty"`
+	ModelVersion   string           `json:"model_version,omitempty"`
+	Backend        string           `json:"backend,omitempty"`
+	LatencyMs      int64            `json:"latency_ms"`
+	CreatedAt      time.Time        `json:"created_at"`
+}
+
+// DetectionEntry is one object found by L0, for drawing overlays on the image
+type DetectionEntry struct {
+	Label      string      `json:"label"`
+	Confidence float64     `json:"confidence"`
+	BBox       BoundingBox `json:"bbox"`
+
+	// Per-object L1 result, absent when only the whole image was classified
+	Category           string  `json:"category,omitempty"`
+	SubCategory        string  `json:"sub_category,omitempty"`
+	CategoryConfidence float64 `json:"category_confidence,omitempty"`
+	BinNumber          int     `json:"bin_number,omitempty"`
+	BinLabel           string  `json:"bin_label,omitempty"`
+}
+
+// BoundingBox is a box as fractions (0-1) of the image width and height, origin at the top-left corner
+type BoundingBox struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
+}
+
+// ReviewEntry is one operator correction of a record's label
+type ReviewEntry struct {
+	ID                uuid.UUID `json:"id"`
+	Reason            string    `json:"reason,omitempty"`
+	PreviousCategory  string    `json:"previous_category"`
+	PreviousBinNumber int       `json:"previous_bin_number"`
+	Category          string    `json:"category"`
+	SubCategory       string    `json:"sub_category,omitempty"`
+	BinNumber         int       `json:"bin_number"`
+	BinLabel          string    `json:"bin_label"`
+	Reviewer          string    `json:"reviewer"`
+	Note              string    `json:"note,omitempty"`
+	ReviewedAt        time.Time `json:"reviewed_at"`
+}
+
 type ListTrashResponse struct {
 	Data       []TrashResponse `json:"data"`
 	Pagination Pagination      `json:"pagination"`
@@ -0,0 +1,80 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BinRuleSet is one version of the rules that decide which bin an item goes
+// in. Sets are never edited: a change is saved as a new version, and exactly
+// one version is active at a time.
+type BinRuleSet struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Version     int        `gorm:"not null;uniqueIndex" json:"version"`
+	Active      bool       `gorm:"not null;default:false;index" json:"active"`
+	Note        string     `gorm:"type:text" json:"note"`
+	CreatedBy   string     `gorm:"type:varchar(100)" json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	ActivatedAt *time.Time `json:"activated_at"` // Last time the version was made active
+
+	// Rules in evaluation order, the first match wins
+	Rules []BinRule `gorm:"foreignKey:RuleSetID;constraint:OnDelete:CASCADE" json:"rules,omitempty"`
+}
+
+func (BinRuleSet) TableName() string {
+	return "bin_rule_sets"
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (s *BinRuleSet) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
+
+// BinRule maps a classification to a bin. Empty conditions match anything;
+// text conditions are compared case-insensitively.
+type BinRule struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	RuleSetID uuid.UUID `gorm:"type:uuid;not null;index" json:"rule_set_id"`
+	Position  int       `gorm:"not null" json:"position"`
+
+	// Conditions
+	Category      string  `gorm:"type:varchar(50)" json:"category"`
+	SubCategory   string  `gorm:"type:varchar(50)" json:"sub_category"`
+	L0Label       string  `gorm:"type:varchar(50)" json:"l0_label"`
+	MinConfidence float64 `gorm:"type:decimal(5,4)" json:"min_confidence"` // L1 confidence
+
+	// Result
+	BinNumber int    `gorm:"type:int;not null" json:"bin_number"`
+	BinLabel  string `gorm:"type:varchar(50)" json:"bin_label"` // Thai label
+}
+
+func (BinRule) TableName() string {
+	return "bin_rules"
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (r *BinRule) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
+
+// Matches reports whether the rule applies to a classification
+func (r *BinRule) Matches(category, subCategory, l0Label string, confidence float64) bool {
+	return matchesCondition(r.Category, category) &&
+		matchesCondition(r.SubCategory, subCategory) &&
+		matchesCondition(r.L0Label, l0Label) &&
+		confidence >= r.MinConfidence
+}
+
+// matchesCondition compares a text condition, an empty one matches anything
+func matchesCondition(condition, value string) bool {
+	return condition == "" || strings.EqualFold(condition, value)
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Classification is one AI result for a trash record. Every successful
+// classification (initial, retried or reclassified) adds a row; the latest one
+// is also copied onto the record.
+type Classification struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrashRecordID uuid.UUID `gorm:"type:uuid;not null;index:idx_classifications_record,priority:1" json:"trash_record_id"`
+
+	// L1 (Trash-Net) result
+	Category    string  `gorm:"type:varchar(50)" json:"category"`
+	SubCategory string  `gorm:"type:varchar(50)" json:"sub_category"`
+	Confidence  float64 `gorm:"type:decimal(5,4)" json:"confidence"`
+	BinNumber   int     `gorm:"type:int" json:"bin_number"`
+	BinLabel    string  `gorm:"type:varchar(50)" json:"bin_label"`
+
+	// Bin suggested by the AI service, used only when no bin rule matched
+	AIBinNumber    int    `gorm:"type:int" json:"ai_bin_number"`
+	AIBinLabel     string `gorm:"type:varchar(50)" json:"ai_bin_label"`
+	BinRuleVersion int    `gorm:"type:int" json:"bin_rule_version"` // Rule set that chose the bin, 0 if the AI suggestion was used
+
+	// L0 (YOLO) object detection
+	L0Detected   bool        `gorm:"not null;default:false" json:"l0_detected"`
+	L0Label      string      `gorm:"type:varchar(50)" json:"l0_label"`
+	L0Confidence float64     `gorm:"type:decimal(5,4)" json:"l0_confidence"`
+	Detections   []Detection `gorm:"foreignKey:ClassificationID;constraint:OnDelete:CASCADE" json:"detections,omitempty"` // Every object found, L0Label is the most confident
+
+	// Model as reported by the AI service, empty if it did not say
+	ModelName    string `gorm:"type:varchar(100)" json:"model_name"`
+	ModelVersion string `gorm:"type:varchar(50);index" json:"model_version"`
+	Backend      string `gorm:"type:varchar(50)" json:"backend"` // AI_BACKENDS entry that served the call
+
+	LatencyMs int64     `gorm:"type:int" json:"latency_ms"` // Time to get the result, including retries
+	CreatedAt time.Time `gorm:"index:idx_classifications_record,priority:2" json:"created_at"`
+}
+
+func (Classification) TableName() string {
+	return "classifications"
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (c *Classification) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClassificationJobStatus tracks a queued classification in async mode
+type ClassificationJobStatus string
+
+const (
+	ClassificationJobQueued  ClassificationJobStatus = "queued"  // Waiting for a worker (or for its retry time)
+	ClassificationJobRunning ClassificationJobStatus = "running" // Claimed by a worker
+	ClassificationJobDone    ClassificationJobStatus = "done"    // Record classified
+	ClassificationJobFailed  ClassificationJobStatus = "failed"  // Gave up after the last attempt
+)
+
+type ClassificationJob struct {
+	ID            uuid.UUID               `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrashRecordID uuid.UUID               `gorm:"type:uuid;not null;index" json:"trash_record_id"`
+	Status        ClassificationJobStatus `gorm:"type:varchar(20);not null;default:queued;index:idx_classification_jobs_pickup,priority:1" json:"status"`
+	RunAt         time.Time               `gorm:"not null;index:idx_classification_jobs_pickup,priority:2" json:"run_at"` // Not picked up before this time
+	Attempts      int                     `gorm:"not null;default:0" json:"attempts"`
+	LockedAt      *time.Time              `json:"locked_at,omitempty"` // When a worker claimed it, used to recover jobs of crashed workers
+	LastError     string                  `gorm:"type:text" json:"last_error,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (ClassificationJob) TableName() string {
+	return "classification_jobs"
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (j *ClassificationJob) BeforeCreate(tx *gorm.DB) error {
+	if j.ID == uuid.Nil {
+		j.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClassificationReview is an operator's correction of a record's label. The
+// label the record had before is kept so reviews can be audited and used to
+// measure the model.
+type ClassificationReview struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TrashRecordID uuid.UUID `gorm:"type:uuid;not null;index" json:"trash_record_id"`
+	Reason        string    `gorm:"type:varchar(30)" json:"reason"` // Why the record was queued, empty if reviewed unprompted
+
+	// Label before the review
+	PreviousCategory    string  `gorm:"type:varchar(50)" json:"previous_category"`
+	PreviousSubCategory string  `gorm:"type:varchar(50)" json:"previous_sub_category"`
+	PreviousBinNumber   int     `gorm:"type:int" json:"previous_bin_number"`
+	PreviousConfidence  float64 `gorm:"type:decimal(5,4)" json:"previous_confidence"`
+
+	// Label set by the reviewer
+	Category    string `gorm:"type:varchar(50);not null" json:"category"`
+	SubCategory string `gorm:"type:varchar(50)" json:"sub_category"`
+	BinNumber   int    `gorm:"type:int;not null" json:"bin_number"`
+	BinLabel    string `gorm:"type:varchar(50)" json:"bin_label"`
+
+	Reviewer  string    `gorm:"type:varchar(100);not null;index" json:"reviewer"`
+	Note      string    `gorm:"type:text" json:"note"`
+	CreatedAt time.Time `json:"created_at"` // When the review was submitted
+}
+
+func (ClassificationReview) TableName() string {
+	return "classification_reviews"
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (r *ClassificationReview) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	return nil
+}
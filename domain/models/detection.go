@@ -0,0 +1,52 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Detection is one object the L0 detector found in an image, with its
+// bounding box and, when the AI service classified each object separately,
+// its own L1 category and bin
+type Detection struct {
+	ID               uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ClassificationID uuid.UUID `gorm:"type:uuid;not null;index" json:"classification_id"`
+	TrashRecordID    uuid.UUID `gorm:"type:uuid;not null;index" json:"trash_record_id"`
+
+	Label      string  `gorm:"type:varchar(50)" json:"label"`
+	Confidence float64 `gorm:"type:decimal(5,4)" json:"confidence"`
+
+	// Bounding box as fractions of the image size, origin at the top-left corner
+	BoxX      float64 `gorm:"type:decimal(7,6)" json:"box_x"`
+	BoxY      float64 `gorm:"type:decimal(7,6)" json:"box_y"`
+	BoxWidth  float64 `gorm:"type:decimal(7,6)" json:"box_width"`
+	BoxHeight float64 `gorm:"type:decimal(7,6)" json:"box_height"`
+
+	// Per-object L1 result, empty when only the whole image was classified
+	Category           string  `gorm:"type:varchar(50)" json:"category"`
+	SubCategory        string  `gorm:"type:varchar(50)" json:"sub_category"`
+	CategoryConfidence float64 `gorm:"type:decimal(5,4)" json:"category_confidence"`
+	BinNumber          int     `gorm:"type:int" json:"bin_number"`
+	BinLabel           string  `gorm:"type:varchar(50)" json:"bin_label"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (Detection) TableName() string {
+	return "detections"
+}
+
+// HasBox reports whether the detector returned a bounding box for the object
+func (d *Detection) HasBox() bool {
+	return d.BoxWidth > 0 && d.BoxHeight > 0
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (d *Detection) BeforeCreate(tx *gorm.DB) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ShadowClassification is the result of a candidate model for an image the
+// production model classified, kept apart from the record so it never affects
+// what devices see. The primary result is copied so agreement can be counted
+// without joining the classification history.
+type ShadowClassification struct {
+	ID uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	// No foreign key: in sync mode the shadow call can finish before the record is saved
+	TrashRecordID uuid.UUID `gorm:"type:uuid;not null;index" json:"trash_record_id"`
+
+	// Production result the shadow is compared against
+	PrimaryCategory     string  `gorm:"type:varchar(50)" json:"primary_category"`
+	PrimaryConfidence   float64 `gorm:"type:decimal(5,4)" json:"primary_confidence"`
+	PrimaryModelVersion string  `gorm:"type:varchar(50)" json:"primary_model_version"`
+
+	// Candidate model result, empty when the call failed
+	Category     string  `gorm:"type:varchar(50)" json:"category"`
+	SubCategory  string  `gorm:"type:varchar(50)" json:"sub_category"`
+	Confidence   float64 `gorm:"type:decimal(5,4)" json:"confidence"`
+	BinNumber    int     `gorm:"type:int" json:"bin_number"`
+	L0Detected   bool    `gorm:"not null;default:false" json:"l0_detected"`
+	L0Label      string  `gorm:"type:varchar(50)" json:"l0_label"`
+	L0Confidence float64 `gorm:"type:decimal(5,4)" json:"l0_confidence"`
+	ModelName    string  `gorm:"type:varchar(100)" json:"model_name"`
+	ModelVersion string  `gorm:"type:varchar(50);index" json:"model_version"`
+
+	Agreed    bool      `gorm:"not null;default:false" json:"agreed"` // Same category as the primary
+	Error     string    `gorm:"type:text" json:"error,omitempty"`
+	LatencyMs int64     `gorm:"type:int" json:"latency_ms"`
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+func (ShadowClassification) TableName() string {
+	return "shadow_classifications"
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (s *ShadowClassification) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == uuid.Nil {
+		s.ID = uuid.New()
+	}
+	return nil
+}
@@ -7,21 +7,80 @@ import (
 	"gorm.io/gorm"
 )
 
+// ClassificationStatus tracks whether a record has been classified
+type ClassificationStatus string
+
+const (
+	ClassificationPending    ClassificationStatus = "pending"    // Queued for classification (async mode)
+	ClassificationClassified ClassificationStatus = "classified" // AI result stored
+	ClassificationFailed     ClassificationStatus = "failed"     // See ClassifyError
+)
+
+// ReviewStatus tracks whether an operator has to check the AI result
+type ReviewStatus string
+
+const (
+	ReviewNone     ReviewStatus = ""         // AI result accepted as is
+	ReviewPending  ReviewStatus = "pending"  // Waiting in the review queue, see ReviewReason
+	ReviewReviewed ReviewStatus = "reviewed" // Label set by an operator; later AI results do not replace it
+)
+
+// Reasons a record is put in the review queue
+const (
+	ReviewReasonLowConfidence = "low_confidence" // L1 confidence below the review threshold
+	ReviewReasonNoObject      = "no_object"      // L0 did not detect an object
+)
+
 type TrashRecord struct {
-	ID        uuid.UUID      `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	DeviceID  string         `gorm:"type:varchar(20);not null;index" json:"device_id"`
-	ImageURL  string         `gorm:"type:text;not null" json:"image_url"`
-	Latitude  float64        `gorm:"type:decimal(10,8);not null" json:"latitude"`
-	Longitude float64        `gorm:"type:decimal(11,8);not null" json:"longitude"`
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	DeviceID  string    `gorm:"type:varchar(20);not null;index" json:"device_id"`
+	ImageURL  string    `gorm:"type:text;not null" json:"image_url"`
+	Latitude  float64   `gorm:"type:decimal(10,8);not null" json:"latitude"`
+	Longitude float64   `gorm:"type:decimal(11,8);not null" json:"longitude"`
+
+	// Stored image metadata (verified against storage on create)
+	ImageKey         string `gorm:"type:text" json:"image_key"`
+	ImageSize        int64  `gorm:"type:bigint" json:"image_size"`
+	ImageContentType string `gorm:"type:varchar(50)" json:"image_content_type"`
+	ImageETag        string `gorm:"type:varchar(100)" json:"image_etag"`
+
+	// Content fingerprints used to detect duplicate submissions
+	ImageSHA256 string `gorm:"type:char(64);index" json:"image_sha256"`           // Hex SHA-256 of the stored bytes, i.e. after EXIF stripping
+	ImagePHash  *int64 `gorm:"column:image_phash;type:bigint" json:"image_phash"` // 64-bit difference hash, nil if undecodable
+
+	// Metadata extracted from the photo's EXIF before it was stripped
+	CapturedAt       *time.Time `json:"captured_at"`
+	ImageOrientation int        `gorm:"type:smallint" json:"image_orientation"` // EXIF orientation 1-8, 0 if absent
+	ExifLatitude     *float64   `gorm:"type:decimal(10,8)" json:"exif_latitude"`
+	ExifLongitude    *float64   `gorm:"type:decimal(11,8)" json:"exif_longitude"`
+	ExifDistance     *float64   `gorm:"type:double precision" json:"exif_distance"`            // Meters between EXIF GPS and submitted coordinates
+	LocationMismatch bool       `gorm:"not null;default:false;index" json:"location_mismatch"` // EXIF GPS disagrees with submitted coordinates
+
+	// Resized variants generated after creation (empty until ready)
+	ThumbnailKey string `gorm:"type:text" json:"thumbnail_key"`
+	MediumKey    string `gorm:"type:text" json:"medium_key"`
 
 	// AI Classification fields
-	Category      string    `gorm:"type:varchar(50)" json:"category"`       // cardboard, glass, metal, paper, plastic, trash
-	SubCategory   string    `gorm:"type:varchar(50)" json:"sub_category"`   // For L2 classification (e.g., PET, HDPE)
-	Confidence    float64   `gorm:"type:decimal(5,4)" json:"confidence"`    // 0.0000 - 1.0000
-	BinNumber     int       `gorm:"type:int" json:"bin_number"`             // 1-6
-	BinLabel      string    `gorm:"type:varchar(50)" json:"bin_label"`      // Thai label
-	ClassifyError string    `gorm:"type:text" json:"classify_error"`        // Error message if classification failed
-	ClassifiedAt  time.Time `json:"classified_at"`
+	ClassificationStatus ClassificationStatus `gorm:"type:varchar(20);not null;default:classified;index" json:"classification_status"`
+	Category             string               `gorm:"type:varchar(50)" json:"category"`     // cardboard, glass, metal, paper, plastic, trash
+	SubCategory          string               `gorm:"type:varchar(50)" json:"sub_category"` // For L2 classification (e.g., PET, HDPE)
+	Confidence           float64              `gorm:"type:decimal(5,4)" json:"confidence"`  // 0.0000 - 1.0000
+	BinNumber            int                  `gorm:"type:int" json:"bin_number"`           // 1-6
+	BinLabel             string               `gorm:"type:varchar(50)" json:"bin_label"`    // Thai label
+	ClassifyError        string               `gorm:"type:text" json:"classify_error"`      // Error message if classification failed
+	ClassifiedAt         time.Time            `json:"classified_at"`
+
+	// Human review of the AI result
+	ReviewStatus ReviewStatus `gorm:"type:varchar(20);not null;default:'';index" json:"review_status"`
+	ReviewReason string       `gorm:"type:varchar(30)" json:"review_reason"`
+
+	// Every AI result for the record, oldest first. New entries appended by the
+	// classification service are inserted on Create and UpdateClassification;
+	// FindByID does not load the history.
+	Classifications []Classification `gorm:"foreignKey:TrashRecordID;constraint:OnDelete:CASCADE" json:"classifications,omitempty"`
+
+	// Operator corrections, oldest first. Not loaded by FindByID.
+	Reviews []ClassificationReview `gorm:"foreignKey:TrashRecordID;constraint:OnDelete:CASCADE" json:"reviews,omitempty"`
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UploadSessionStatus tracks the lifecycle of an issued upload URL
+type UploadSessionStatus string
+
+const (
+	UploadSessionPending UploadSessionStatus = "pending" // URL issued, no trash record yet
+	UploadSessionUsed    UploadSessionStatus = "used"    // Consumed by POST /api/trash
+	UploadSessionExpired UploadSessionStatus = "expired" // Never used before the URL expired
+)
+
+type UploadSession struct {
+	ID            uuid.UUID           `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	DeviceID      string              `gorm:"type:varchar(20);not null;index" json:"device_id"`
+	ObjectKey     string              `gorm:"type:text;not null;uniqueIndex" json:"object_key"`
+	ContentType   string              `gorm:"type:varchar(50)" json:"content_type"`
+	Status        UploadSessionStatus `gorm:"type:varchar(20);not null;default:pending;index" json:"status"`
+	ExpiresAt     time.Time           `gorm:"not null" json:"expires_at"`
+	UsedAt        *time.Time          `json:"used_at,omitempty"`
+	TrashRecordID *uuid.UUID          `gorm:"type:uuid" json:"trash_record_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (UploadSession) TableName() string {
+	return "upload_sessions"
+}
+
+// BeforeCreate hook to generate UUID if not set
+func (u *UploadSession) BeforeCreate(tx *gorm.DB) error {
+	if u.ID == uuid.Nil {
+		u.ID = uuid.New()
+	}
+	return nil
+}
@@ -2,19 +2,58 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"time"
 )
 
+// ErrAIUnavailable is returned by AI adapters that refuse calls without trying,
+// e.g. while a circuit breaker is open
+var ErrAIUnavailable = errors.New("AI service unavailable")
+
 // ClassificationResult represents the AI classification response
 type ClassificationResult struct {
-	Category     string  `json:"category"`
-	SubCategory  string  `json:"sub_category,omitempty"`
-	Confidence   float64 `json:"confidence"`
-	BinNumber    int     `json:"bin_number"`
-	BinLabel     string  `json:"bin_label"`
-	Message      string  `json:"message"`
-	L0Detected   bool    `json:"l0_detected"`             // L0 พบวัตถุหรือไม่
-	L0Label      string  `json:"l0_label,omitempty"`      // YOLO detected object (bottle, cup, etc.)
-	L0Confidence float64 `json:"l0_confidence,omitempty"` // YOLO confidence
+	Category     string      `json:"category"`
+	SubCategory  string      `json:"sub_category,omitempty"`
+	Confidence   float64     `json:"confidence"`
+	BinNumber    int         `json:"bin_number"`
+	BinLabel     string      `json:"bin_label"`
+	Message      string      `json:"message"`
+	L0Detected   bool        `json:"l0_detected"`             // L0 พบวัตถุหรือไม่
+	L0Label      string      `json:"l0_label,omitempty"`      // YOLO detected object (bottle, cup, etc.)
+	L0Confidence float64     `json:"l0_confidence,omitempty"` // YOLO confidence
+	Detections   []Detection `json:"detections,omitempty"`    // Every object YOLO found
+	ModelName    string      `json:"model_name,omitempty"`    // Model reported by the AI service
+	ModelVersion string      `json:"model_version,omitempty"`
+	Backend      string      `json:"backend,omitempty"` // Configured backend that produced the result
+}
+
+// ImageData is an image passed to the AI service by value, for services that
+// cannot read the storage (private buckets, local storage)
+type ImageData struct {
+	Data        []byte
+	ContentType string
+	URL         string // Where the image can be read, for adapters configured to send URLs; may be empty
+}
+
+// Detection is one object found by L0, optionally classified by L1 on its own
+type Detection struct {
+	Label      string      `json:"label"`
+	Confidence float64     `json:"confidence"`
+	BBox       BoundingBox `json:"bbox"`
+
+	Category           string  `json:"category,omitempty"`
+	SubCategory        string  `json:"sub_category,omitempty"`
+	CategoryConfidence float64 `json:"category_confidence,omitempty"`
+	BinNumber          int     `json:"bin_number,omitempty"`
+	BinLabel           string  `json:"bin_label,omitempty"`
+}
+
+// BoundingBox is a box as fractions (0-1) of the image size, origin at the top-left corner
+type BoundingBox struct {
+	X      float64 `json:"x"`
+	Y      float64 `json:"y"`
+	Width  float64 `json:"width"`
+	Height float64 `json:"height"`
 }
 
 // AIAdapter defines the interface for AI classification service
@@ -22,6 +61,21 @@ type AIAdapter interface {
 	// ClassifyImage sends an image URL to AI service and returns classification result
 	ClassifyImage(ctx context.Context, imageURL string) (*ClassificationResult, error)
 
+	// ClassifyImageData sends the image itself to AI service and returns classification result
+	ClassifyImageData(ctx context.Context, image ImageData) (*ClassificationResult, error)
+
 	// Health checks if AI service is available
 	Health(ctx context.Context) (bool, error)
 }
+
+// CircuitState is a point-in-time view of the circuit breaker guarding the AI service
+type CircuitState struct {
+	State               string     `json:"state"` // closed, open, half_open
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+}
+
+// CircuitReporter is implemented by AI adapters that guard calls with a circuit breaker
+type CircuitReporter interface {
+	CircuitState() CircuitState
+}
@@ -2,26 +2,96 @@ package ports
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net/url"
 	"time"
 )
 
+// ErrContentLengthRequired is returned when an adapter can only bound the upload
+// size by signing the exact Content-Length and the caller did not provide it
+var ErrContentLengthRequired = errors.New("content length is required to enforce the upload size limit")
+
+// ErrUnsupportedChecksum is returned when the provider cannot verify the requested upload checksum
+var ErrUnsupportedChecksum = errors.New("upload checksum is not supported by the storage provider")
+
+// ErrObjectNotFound is returned when an object does not exist in storage
+var ErrObjectNotFound = errors.New("object not found")
+
 // StorageAdapter defines the interface for cloud storage operations
 // This allows us to easily swap between different storage providers (R2, S3, GCS)
 // without changing business logic
 type StorageAdapter interface {
 	// GeneratePresignedUploadURL creates a presigned URL for uploading objects
-	GeneratePresignedUploadURL(ctx context.Context, key string, expiry time.Duration) (*PresignedURLResponse, error)
+	GeneratePresignedUploadURL(ctx context.Context, key string, opts UploadOptions) (*PresignedURLResponse, error)
 
 	// GeneratePublicURL returns the public URL for accessing an object
 	GeneratePublicURL(key string) string
 
+	// GeneratePresignedDownloadURL creates a short-lived URL for reading an object from a private bucket
+	GeneratePresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+
 	// DeleteObject removes an object from storage
 	DeleteObject(ctx context.Context, key string) error
+
+	// PutObject uploads an object from the server side
+	PutObject(ctx context.Context, key string, body io.Reader, contentType string) error
+
+	// GetObject opens an object for reading, or returns ErrObjectNotFound. The caller must close it.
+	GetObject(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// StatObject returns metadata for an object, or ErrObjectNotFound if it does not exist
+	StatObject(ctx context.Context, key string) (*ObjectInfo, error)
+
+	// ListObjects calls fn for every object whose key starts with prefix.
+	// Listing stops at the first error returned by fn.
+	ListObjects(ctx context.Context, prefix string, fn func(ObjectInfo) error) error
+
+	// CopyObject copies an object to a new key within the same storage
+	CopyObject(ctx context.Context, srcKey, dstKey string) error
+}
+
+// ObjectInfo describes a stored object
+type ObjectInfo struct {
+	Key          string
+	Size         int64
+	ContentType  string
+	ETag         string
+	LastModified time.Time
+}
+
+// UploadOptions constrains what a presigned upload URL accepts
+type UploadOptions struct {
+	Expiry         time.Duration
+	ContentType    string // Exact content type the client must send
+	ContentLength  int64  // Exact size in bytes, 0 if unknown
+	MaxBytes       int64  // Upper bound on the object size, 0 for no limit
+	ContentMD5     string // Base64 MD5 the body must match (optional)
+	ChecksumSHA256 string // Base64 SHA-256 the body must match (optional)
 }
 
 // PresignedURLResponse contains the URLs for uploading and accessing an object
 type PresignedURLResponse struct {
-	UploadURL string // Presigned URL for uploading (PUT request)
-	PublicURL string // Public URL for accessing the uploaded file
-	ExpiresIn int64  // Expiration time in seconds
+	UploadURL  string            // Presigned URL for uploading
+	Method     string            // HTTP method to use: PUT, or POST for form-based policies
+	Headers    map[string]string // Headers the client must send with a PUT upload
+	FormFields map[string]string // Form fields the client must send with a POST upload
+	PublicURL  string            // Public URL for accessing the uploaded file
+	ExpiresIn  int64             // Expiration time in seconds
+}
+
+// LocalFileStore is implemented by storage adapters that receive uploads and
+// serve objects through the API itself instead of a cloud provider
+type LocalFileStore interface {
+	// VerifyUpload checks that a signed upload URL is valid and that the body satisfies its constraints
+	VerifyUpload(key string, query url.Values, contentType string, body []byte) error
+
+	// PutObject writes an object to storage
+	PutObject(ctx context.Context, key string, body io.Reader, contentType string) error
+
+	// VerifyDownload checks the signature of a read URL when the store is private
+	VerifyDownload(key string, query url.Values) error
+
+	// ObjectPath resolves an object key to a local file path
+	ObjectPath(key string) (string, error)
 }
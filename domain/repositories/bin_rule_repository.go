@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+
+	"gofiber-smart-trash/domain/models"
+)
+
+type BinRuleRepository interface {
+	// FindActive returns the active rule set with its rules, or gorm.ErrRecordNotFound if none is active
+	FindActive(ctx context.Context) (*models.BinRuleSet, error)
+
+	// FindByVersion returns a rule set with its rules
+	FindByVersion(ctx context.Context, version int) (*models.BinRuleSet, error)
+
+	// List returns every rule set without its rules, newest first
+	List(ctx context.Context) ([]models.BinRuleSet, error)
+
+	// Create stores a rule set as the next version and, with activate, makes it
+	// the active one in the same transaction. The assigned version is set on ruleSet.
+	Create(ctx context.Context, ruleSet *models.BinRuleSet, activate bool) error
+
+	// Activate makes a version the active one, returning gorm.ErrRecordNotFound if it does not exist
+	Activate(ctx context.Context, version int) error
+}
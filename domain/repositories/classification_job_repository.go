@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gofiber-smart-trash/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type ClassificationJobRepository interface {
+	Enqueue(ctx context.Context, job *models.ClassificationJob) error
+
+	// ClaimNext locks the next due job for a worker and marks it running, also
+	// reclaiming running jobs locked before staleBefore. Returns nil if no job is due.
+	ClaimNext(ctx context.Context, staleBefore time.Time) (*models.ClassificationJob, error)
+
+	Complete(ctx context.Context, id uuid.UUID) error
+
+	// Retry puts a job back in the queue to run again at runAt
+	Retry(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error
+
+	Fail(ctx context.Context, id uuid.UUID, lastError string) error
+}
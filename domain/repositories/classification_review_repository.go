@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"gofiber-smart-trash/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type ClassificationReviewRepository interface {
+	// FindPending returns records waiting for review, oldest first, and the total count
+	FindPending(ctx context.Context, filter ReviewFilter) ([]models.TrashRecord, int64, error)
+
+	// Submit stores a review and applies its label to the record in one transaction
+	Submit(ctx context.Context, trash *models.TrashRecord, review *models.ClassificationReview) error
+
+	// FindByTrashID returns the reviews of a record, oldest first
+	FindByTrashID(ctx context.Context, trashID uuid.UUID) ([]models.ClassificationReview, error)
+}
+
+type ReviewFilter struct {
+	DeviceID string
+	Reason   string
+	Limit    int
+	Offset   int
+}
@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gofiber-smart-trash/domain/models"
+)
+
+type ShadowClassificationRepository interface {
+	// Create stores one shadow result
+	Create(ctx context.Context, shadow *models.ShadowClassification) error
+
+	// CountPairs counts successful shadow results per primary and shadow category
+	CountPairs(ctx context.Context, filter ShadowFilter) ([]ShadowPairCount, error)
+
+	// CountFailed counts shadow calls that returned no result
+	CountFailed(ctx context.Context, filter ShadowFilter) (int64, error)
+}
+
+type ShadowFilter struct {
+	CreatedFrom  *time.Time
+	CreatedTo    *time.Time // Exclusive
+	ModelVersion string     // Shadow model version
+}
+
+// ShadowPairCount is one cell of the primary/shadow confusion matrix
+type ShadowPairCount struct {
+	PrimaryCategory string
+	Category        string
+	Count           int64
+}
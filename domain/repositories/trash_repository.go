@@ -2,6 +2,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"gofiber-smart-trash/domain/models"
 
@@ -12,6 +13,49 @@ type TrashRepository interface {
 	Create(ctx context.Context, trash *models.TrashRecord) error
 	FindByID(ctx context.Context, id uuid.UUID) (*models.TrashRecord, error)
 	FindAll(ctx context.Context, filter TrashFilter) ([]models.TrashRecord, int64, error)
+	UpdateImageVariants(ctx context.Context, id uuid.UUID, thumbnailKey, mediumKey string) error
+
+	// CreateWithClassificationJob inserts a record and its queued classification job in one transaction
+	CreateWithClassificationJob(ctx context.Context, trash *models.TrashRecord, job *models.ClassificationJob) error
+
+	// UpdateClassification stores the classification fields and status of a record
+	// and inserts the entries of trash.Classifications not saved yet
+	UpdateClassification(ctx context.Context, trash *models.TrashRecord) error
+
+	// FindClassifications returns the classification history of a record with its detections, oldest first
+	FindClassifications(ctx context.Context, trashID uuid.UUID) ([]models.Classification, error)
+
+	// FindRecentByDevice returns the device's records created at or after since, newest first
+	FindRecentByDevice(ctx context.Context, deviceID string, since time.Time) ([]models.TrashRecord, error)
+
+	// FindBatchAfterID returns up to limit records (including soft-deleted ones) with an ID greater than afterID, ordered by ID
+	FindBatchAfterID(ctx context.Context, afterID uuid.UUID, limit int) ([]models.TrashRecord, error)
+
+	// UpdateImageLocations rewrites the image URL and key of several records in one transaction
+	UpdateImageLocations(ctx context.Context, locations []ImageLocation) error
+
+	// CountForReclassify counts the records matching filter
+	CountForReclassify(ctx context.Context, filter ReclassifyFilter) (int64, error)
+
+	// FindForReclassify returns up to limit records matching filter with an ID greater than afterID, ordered by ID
+	FindForReclassify(ctx context.Context, filter ReclassifyFilter, afterID uuid.UUID, limit int) ([]models.TrashRecord, error)
+
+	// FindForExport returns up to limit classified records matching filter with an ID
+	// greater than afterID, ordered by ID, with their classification history loaded
+	FindForExport(ctx context.Context, filter DatasetFilter, afterID uuid.UUID, limit int) ([]models.TrashRecord, error)
+
+	// FindExistingImageURLs returns the subset of imageURLs referenced by any record, including soft-deleted ones
+	FindExistingImageURLs(ctx context.Context, imageURLs []string) ([]string, error)
+
+	// FindExistingImageKeys returns the subset of imageKeys referenced by any record, including soft-deleted ones
+	FindExistingImageKeys(ctx context.Context, imageKeys []string) ([]string, error)
+}
+
+// ImageLocation is where a record's original image is stored
+type ImageLocation struct {
+	ID       uuid.UUID
+	ImageURL string
+	ImageKey string
 }
 
 type TrashFilter struct {
@@ -19,3 +63,22 @@ type TrashFilter struct {
 	Limit    int
 	Offset   int
 }
+
+// ReclassifyFilter selects records for batch reclassification. Set fields are
+// combined with AND, except EmptyCategory and HasError which match records
+// satisfying either when both are set.
+type ReclassifyFilter struct {
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time // Exclusive
+	DeviceID      string
+	EmptyCategory bool
+	HasError      bool
+}
+
+// DatasetFilter selects labelled records for a training dataset export
+type DatasetFilter struct {
+	CreatedFrom    *time.Time
+	CreatedTo      *time.Time // Exclusive
+	MinConfidence  float64    // Applies to AI labels only; reviewed records always qualify
+	ReviewStatuses []models.ReviewStatus
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"gofiber-smart-trash/domain/models"
+
+	"github.com/google/uuid"
+)
+
+type UploadSessionRepository interface {
+	Create(ctx context.Context, session *models.UploadSession) error
+	FindByID(ctx context.Context, id uuid.UUID) (*models.UploadSession, error)
+	FindByObjectKey(ctx context.Context, objectKey string) (*models.UploadSession, error)
+
+	// Renew extends a session that has not been used yet and returns it to pending
+	Renew(ctx context.Context, id uuid.UUID, expiresAt time.Time) error
+
+	// Claim atomically marks a pending session as used by a trash record.
+	// Returns false if the session was not pending.
+	Claim(ctx context.Context, id uuid.UUID, trashRecordID uuid.UUID) (bool, error)
+
+	// Release returns a claimed session to pending, e.g. when record creation failed
+	Release(ctx context.Context, id uuid.UUID) error
+
+	// ExpirePending marks pending sessions that expired before the given time as expired
+	ExpirePending(ctx context.Context, before time.Time) (int64, error)
+}
@@ -0,0 +1,31 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gofiber-smart-trash/domain/dto"
+)
+
+// ErrBinRuleSetNotFound is returned when a bin rule version does not exist
+var ErrBinRuleSetNotFound = errors.New("bin rule set not found")
+
+// BinMappingService decides which bin a classified item goes in from versioned
+// rules kept in the database, so local sorting rules can change without
+// redeploying the AI service. The bin suggested by the AI is only a fallback.
+type BinMappingService interface {
+	// Assign returns the bin of the first matching rule of the active rule set
+	// and that set's version, or version 0 when no rule matches
+	Assign(category, subCategory, l0Label string, confidence float64) (binNumber int, binLabel string, version int)
+
+	// Reload reads the active rule set from the database, picking up changes
+	// made through another instance
+	Reload(ctx context.Context) error
+
+	// Admin API
+	ActiveRuleSet(ctx context.Context) (*dto.BinRuleSetResponse, error)
+	ListRuleSets(ctx context.Context) ([]dto.BinRuleSetResponse, error)
+	GetRuleSet(ctx context.Context, version int) (*dto.BinRuleSetResponse, error)
+	CreateRuleSet(ctx context.Context, req *dto.CreateBinRuleSetRequest) (*dto.BinRuleSetResponse, error)
+	ActivateRuleSet(ctx context.Context, version int) (*dto.BinRuleSetResponse, error)
+}
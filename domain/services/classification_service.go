@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/ports"
+)
+
+// ErrClassificationFailed is returned when a reclassification could not get a result from the AI service
+var ErrClassificationFailed = errors.New("classification failed")
+
+// ClassificationService runs AI classification for trash records, either
+// inline during a request or from the Postgres-backed job queue
+type ClassificationService interface {
+	// Classify classifies the record's image and applies the result (or the
+	// error) to trash without saving it. A result is also appended to
+	// trash.Classifications, and flags the record for review when it is
+	// uncertain; a label set by an operator is never replaced.
+	Classify(ctx context.Context, trash *models.TrashRecord) (*ports.ClassificationResult, error)
+
+	// SubmitShadow sends the image of a record created with result to the
+	// shadow model, if one is configured. Call it only once the record is
+	// saved, so the shadow report covers nothing but stored records.
+	SubmitShadow(ctx context.Context, trash *models.TrashRecord, result *ports.ClassificationResult)
+
+	// Reclassify classifies an existing record again and saves the result. On
+	// failure the record keeps its previous classification. The shadow model
+	// is not used.
+	Reclassify(ctx context.Context, trash *models.TrashRecord) (*ports.ClassificationResult, error)
+
+	// ProcessNextJob claims and runs one queued job. Returns false when no job was due.
+	ProcessNextJob(ctx context.Context) (bool, error)
+}
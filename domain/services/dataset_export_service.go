@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"gofiber-smart-trash/domain/dto"
+)
+
+// ErrInvalidExport is returned when dataset export options are invalid
+var ErrInvalidExport = errors.New("invalid dataset export")
+
+// DatasetExportService packages labelled records and their images as a
+// training dataset for the L1 classifier and the L0 detector
+type DatasetExportService interface {
+	// Validate applies defaults to opts and checks them, so callers can reject
+	// bad input before they start streaming the archive
+	Validate(opts *dto.DatasetExportOptions) error
+
+	// Export writes a zip archive of the dataset to w
+	Export(ctx context.Context, opts dto.DatasetExportOptions, w io.Writer) (*dto.DatasetExportReport, error)
+}
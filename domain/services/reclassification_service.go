@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"gofiber-smart-trash/domain/dto"
+)
+
+// ReclassificationService runs AI classification again over existing records,
+// e.g. after a model upgrade or to repair records saved during an AI outage
+type ReclassificationService interface {
+	Reclassify(ctx context.Context, opts dto.ReclassifyOptions) (*dto.ReclassifyReport, error)
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/ports"
+)
+
+// ErrInvalidDateRange is returned when a report's from or to date cannot be parsed
+var ErrInvalidDateRange = errors.New("invalid date range")
+
+// ShadowService runs a candidate model next to the production one on live
+// traffic and reports how often the two agree
+type ShadowService interface {
+	// Submit classifies the image with the candidate model in the background
+	// and stores the result next to primary. It never blocks the caller and
+	// does nothing when no shadow model is configured.
+	Submit(trash *models.TrashRecord, image ports.ImageData, primary *ports.ClassificationResult)
+
+	// AgreementReport compares stored shadow results with the production results
+	AgreementReport(ctx context.Context, req *dto.ShadowReportRequest) (*dto.ShadowReport, error)
+}
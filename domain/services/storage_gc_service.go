@@ -0,0 +1,12 @@
+package services
+
+import (
+	"context"
+
+	"gofiber-smart-trash/domain/dto"
+)
+
+// StorageGCService finds and removes uploaded images that never became trash records
+type StorageGCService interface {
+	CollectOrphans(ctx context.Context, opts dto.StorageGCOptions) (*dto.StorageGCReport, error)
+}
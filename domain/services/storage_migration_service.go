@@ -0,0 +1,13 @@
+package services
+
+import (
+	"context"
+
+	"gofiber-smart-trash/domain/dto"
+)
+
+// StorageMigrationService copies images referenced by trash records to another
+// storage provider and points the records at their new location
+type StorageMigrationService interface {
+	Migrate(ctx context.Context, opts dto.StorageMigrationOptions) (*dto.StorageMigrationReport, error)
+}
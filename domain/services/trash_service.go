@@ -2,15 +2,35 @@ package services
 
 import (
 	"context"
+	"errors"
+	"io"
 
 	"gofiber-smart-trash/domain/dto"
 
 	"github.com/google/uuid"
 )
 
+// ErrInvalidUpload is returned when an upload request violates the upload policy
+var ErrInvalidUpload = errors.New("invalid upload request")
+
+// ErrInvalidImage is returned when a submitted image was not uploaded by the device to our storage
+var ErrInvalidImage = errors.New("invalid image")
+
+// ErrUploadAlreadyUsed is returned when an upload_id has already been turned into a trash record
+var ErrUploadAlreadyUsed = errors.New("upload has already been used")
+
+// ErrTrashNotFound is returned when a trash record does not exist
+var ErrTrashNotFound = errors.New("trash record not found")
+
 type TrashService interface {
-	GenerateUploadURL(ctx context.Context, deviceID string) (*dto.UploadURLResponse, error)
+	GenerateUploadURL(ctx context.Context, req *dto.UploadURLRequest) (*dto.UploadURLResponse, error)
 	CreateTrashRecord(ctx context.Context, req *dto.CreateTrashRequest) (*dto.TrashResponse, error)
+	UploadTrashRecord(ctx context.Context, req *dto.UploadTrashRequest, image io.Reader, size int64) (*dto.TrashResponse, error)
 	GetTrashByID(ctx context.Context, id uuid.UUID) (*dto.TrashResponse, error)
 	ListTrash(ctx context.Context, req *dto.ListTrashRequest) (*dto.ListTrashResponse, error)
+	ReclassifyTrashRecord(ctx context.Context, id uuid.UUID) (*dto.TrashResponse, error)
+
+	// Review queue for uncertain AI results
+	ListPendingReviews(ctx context.Context, req *dto.ListReviewsRequest) (*dto.ListTrashResponse, error)
+	SubmitReview(ctx context.Context, id uuid.UUID, req *dto.SubmitReviewRequest) (*dto.TrashResponse, error)
 }
@@ -4,16 +4,32 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
+	"strconv"
 	"time"
 
 	"gofiber-smart-trash/domain/ports"
 )
 
+// How a ClassifierClient passes images to the AI service
+const (
+	InputURL   = "url"   // JSON body with image_url; the service downloads the image
+	InputBytes = "bytes" // multipart/form-data upload of the image in the "image" field
+)
+
+// ErrImageDataRequired is returned by a client in bytes mode that was only given a URL
+var ErrImageDataRequired = errors.New("AI backend expects image data, not a URL")
+
 // ClassifierClient implements AIAdapter interface
 type ClassifierClient struct {
 	baseURL    string
+	input      string // InputURL or InputBytes
 	httpClient *http.Client
 }
 
@@ -33,6 +49,10 @@ type ClassifyResponse struct {
 	L0Detected   bool    `json:"l0_detected"`             // L0 พบวัตถุหรือไม่
 	L0Label      string  `json:"l0_label,omitempty"`      // YOLO detected object (bottle, cup, etc.)
 	L0Confidence float64 `json:"l0_confidence,omitempty"` // YOLO confidence
+	ModelName    string  `json:"model_name,omitempty"`
+	ModelVersion string  `json:"model_version,omitempty"`
+
+	Detections []ports.Detection `json:"detections,omitempty"` // Every object YOLO found, with normalised boxes
 }
 
 // HealthResponse is the response from health endpoint
@@ -42,10 +62,22 @@ type HealthResponse struct {
 	Device      string `json:"device"`
 }
 
-// NewClassifierClient creates a new AI classifier client
-func NewClassifierClient(baseURL string, timeout int) *ClassifierClient {
+// StatusError is returned when the AI service answers with a non-200 status
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration // From the Retry-After header, 0 if absent
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("AI service returned status %d", e.StatusCode)
+}
+
+// NewClassifierClient creates a new AI classifier client that sends images as
+// input (InputURL or InputBytes)
+func NewClassifierClient(baseURL string, timeout int, input string) *ClassifierClient {
 	return &ClassifierClient{
 		baseURL: baseURL,
+		input:   input,
 		httpClient: &http.Client{
 			Timeout: time.Duration(timeout) * time.Second,
 		},
@@ -54,20 +86,56 @@ func NewClassifierClient(baseURL string, timeout int) *ClassifierClient {
 
 // ClassifyImage sends an image URL to AI service and returns classification result
 func (c *ClassifierClient) ClassifyImage(ctx context.Context, imageURL string) (*ports.ClassificationResult, error) {
+	if c.input == InputBytes {
+		return nil, ErrImageDataRequired
+	}
+
 	// Prepare request body
 	reqBody := ClassifyRequest{ImageURL: imageURL}
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
+	return c.classify(ctx, bytes.NewReader(jsonData), "application/json")
+}
+
+// ClassifyImageData uploads the image to AI service as multipart/form-data. A
+// client in URL mode sends image.URL instead when it is set.
+func (c *ClassifierClient) ClassifyImageData(ctx context.Context, image ports.ImageData) (*ports.ClassificationResult, error) {
+	if c.input != InputBytes && image.URL != "" {
+		return c.ClassifyImage(ctx, image.URL)
+	}
+	if len(image.Data) == 0 {
+		return nil, fmt.Errorf("no image data to send")
+	}
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", `form-data; name="image"; filename="image"`)
+	header.Set("Content-Type", image.ContentType)
+	part, err := form.CreatePart(header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create multipart body: %w", err)
+	}
+	if _, err := part.Write(image.Data); err != nil {
+		return nil, fmt.Errorf("failed to write multipart body: %w", err)
+	}
+	if err := form.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write multipart body: %w", err)
+	}
+	return c.classify(ctx, &body, form.FormDataContentType())
+}
 
+// classify posts a request body to the classify endpoint and parses the result
+func (c *ClassifierClient) classify(ctx context.Context, body io.Reader, contentType string) (*ports.ClassificationResult, error) {
 	// Create HTTP request
 	url := fmt.Sprintf("%s/api/classify", c.baseURL)
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 
 	// Send request
 	resp, err := c.httpClient.Do(req)
@@ -78,7 +146,11 @@ func (c *ClassifierClient) ClassifyImage(ctx context.Context, imageURL string) (
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("AI service returned status %d", resp.StatusCode)
+		statusErr := &StatusError{StatusCode: resp.StatusCode}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			statusErr.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, statusErr
 	}
 
 	// Parse response
@@ -87,6 +159,30 @@ func (c *ClassifierClient) ClassifyImage(ctx context.Context, imageURL string) (
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	// Detections are stored as-is, so keep them within the columns' ranges
+	clamped := 0
+	for i := range classifyResp.Detections {
+		if clampDetection(&classifyResp.Detections[i]) {
+			clamped++
+		}
+	}
+	if clamped > 0 {
+		log.Printf("[AI] Clamped %d of %d detections with out-of-range confidences or boxes", clamped, len(classifyResp.Detections))
+	}
+
+	// Services that only send the detection list still fill the single-object fields
+	if classifyResp.L0Label == "" && len(classifyResp.Detections) > 0 {
+		top := classifyResp.Detections[0]
+		for _, d := range classifyResp.Detections[1:] {
+			if d.Confidence > top.Confidence {
+				top = d
+			}
+		}
+		classifyResp.L0Detected = true
+		classifyResp.L0Label = top.Label
+		classifyResp.L0Confidence = top.Confidence
+	}
+
 	return &ports.ClassificationResult{
 		Category:     classifyResp.Category,
 		SubCategory:  classifyResp.SubCategory,
@@ -97,6 +193,9 @@ func (c *ClassifierClient) ClassifyImage(ctx context.Context, imageURL string) (
 		L0Detected:   classifyResp.L0Detected,
 		L0Label:      classifyResp.L0Label,
 		L0Confidence: classifyResp.L0Confidence,
+		ModelName:    classifyResp.ModelName,
+		ModelVersion: classifyResp.ModelVersion,
+		Detections:   classifyResp.Detections,
 	}, nil
 }
 
@@ -125,3 +224,25 @@ func (c *ClassifierClient) Health(ctx context.Context) (bool, error) {
 
 	return healthResp.Status == "ok" && healthResp.ModelLoaded, nil
 }
+
+// clampDetection limits confidences to 0-1 and the bounding box to the image,
+// reporting whether anything changed. A box with nothing left inside the
+// image, e.g. one in pixels rather than fractions, is dropped.
+func clampDetection(d *ports.Detection) bool {
+	before := *d
+	d.Confidence = clampUnit(d.Confidence)
+	d.CategoryConfidence = clampUnit(d.CategoryConfidence)
+	d.BBox.X = clampUnit(d.BBox.X)
+	d.BBox.Y = clampUnit(d.BBox.Y)
+	d.BBox.Width = min(clampUnit(d.BBox.Width), 1-d.BBox.X)
+	d.BBox.Height = min(clampUnit(d.BBox.Height), 1-d.BBox.Y)
+	if d.BBox.Width == 0 || d.BBox.Height == 0 {
+		d.BBox = ports.BoundingBox{}
+	}
+	return *d != before
+}
+
+// clampUnit limits v to the range 0-1
+func clampUnit(v float64) float64 {
+	return min(max(v, 0), 1)
+}
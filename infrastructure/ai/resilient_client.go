@@ -0,0 +1,187 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"time"
+
+	"gofiber-smart-trash/domain/ports"
+	"gofiber-smart-trash/pkg/breaker"
+)
+
+// ErrCircuitOpen is returned without calling the AI service while the breaker is open
+var ErrCircuitOpen = fmt.Errorf("%w: %w", ports.ErrAIUnavailable, breaker.ErrOpen)
+
+// ResilienceConfig controls retries and the circuit breaker of a ResilientClient
+type ResilienceConfig struct {
+	MaxAttempts        int           // Calls per classification, 1 disables retries
+	BaseDelay          time.Duration // Backoff before the first retry, doubled on each retry
+	MaxDelay           time.Duration
+	MaxElapsed         time.Duration // Deadline for all attempts of a call together, 0 for none
+	BreakerThreshold   int           // Consecutive failures before the breaker opens, 0 disables it
+	BreakerOpenTimeout time.Duration
+}
+
+// ResilientClient wraps an AIAdapter with jittered exponential retries and a
+// circuit breaker. Only network errors and transient statuses are retried and
+// counted against the breaker; a 4xx means the service is up and the request is bad.
+type ResilientClient struct {
+	next    ports.AIAdapter
+	config  ResilienceConfig
+	breaker *breaker.Breaker // nil when disabled
+}
+
+// NewResilientClient creates a new resilient AI client
+func NewResilientClient(next ports.AIAdapter, config ResilienceConfig) *ResilientClient {
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+	if config.BaseDelay <= 0 {
+		config.BaseDelay = 500 * time.Millisecond
+	}
+	if config.MaxDelay < config.BaseDelay {
+		config.MaxDelay = config.BaseDelay
+	}
+	if config.BreakerOpenTimeout <= 0 {
+		config.BreakerOpenTimeout = 30 * time.Second
+	}
+
+	client := &ResilientClient{
+		next:   next,
+		config: config,
+	}
+	if config.BreakerThreshold > 0 {
+		client.breaker = breaker.New("AI service", config.BreakerThreshold, config.BreakerOpenTimeout)
+	}
+	return client
+}
+
+// ClassifyImage classifies an image by URL, retrying transient failures
+func (c *ResilientClient) ClassifyImage(ctx context.Context, imageURL string) (*ports.ClassificationResult, error) {
+	return c.call(ctx, func(ctx context.Context) (*ports.ClassificationResult, error) {
+		return c.next.ClassifyImage(ctx, imageURL)
+	})
+}
+
+// ClassifyImageData classifies image data, retrying transient failures
+func (c *ResilientClient) ClassifyImageData(ctx context.Context, image ports.ImageData) (*ports.ClassificationResult, error) {
+	return c.call(ctx, func(ctx context.Context) (*ports.ClassificationResult, error) {
+		return c.next.ClassifyImageData(ctx, image)
+	})
+}
+
+// call runs classify through the breaker with jittered exponential retries,
+// giving up once MaxElapsed has passed since the first attempt
+func (c *ResilientClient) call(ctx context.Context, classify func(context.Context) (*ports.ClassificationResult, error)) (*ports.ClassificationResult, error) {
+	callCtx := ctx
+	if c.config.MaxElapsed > 0 {
+		var cancel context.CancelFunc
+		callCtx, cancel = context.WithTimeout(ctx, c.config.MaxElapsed)
+		defer cancel()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if c.breaker != nil {
+			if err := c.breaker.Allow(); err != nil {
+				return nil, ErrCircuitOpen
+			}
+		}
+
+		result, err := classify(callCtx)
+		retryable := err != nil && isRetryable(err)
+		// Against the caller's context: running into MaxElapsed counts as a failure
+		c.record(ctx, err, retryable)
+		if err == nil {
+			return result, nil
+		}
+		if !retryable || attempt >= c.config.MaxAttempts || callCtx.Err() != nil {
+			return nil, err
+		}
+
+		delay := c.backoff(attempt, err)
+		if deadline, ok := callCtx.Deadline(); ok && time.Until(deadline) < delay {
+			log.Printf("[AI] Attempt %d/%d failed, no time left to retry: %v", attempt, c.config.MaxAttempts, err)
+			return nil, err
+		}
+		log.Printf("[AI] Attempt %d/%d failed, retrying in %s: %v", attempt, c.config.MaxAttempts, delay.Round(time.Millisecond), err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-callCtx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+// Health checks the AI service directly, bypassing retries and the breaker
+func (c *ResilientClient) Health(ctx context.Context) (bool, error) {
+	return c.next.Health(ctx)
+}
+
+// CircuitState reports the breaker state; a disabled breaker is always closed
+func (c *ResilientClient) CircuitState() ports.CircuitState {
+	if c.breaker == nil {
+		return ports.CircuitState{State: string(breaker.Closed)}
+	}
+	snapshot := c.breaker.Snapshot()
+	return ports.CircuitState{
+		State:               string(snapshot.State),
+		ConsecutiveFailures: snapshot.ConsecutiveFailures,
+		OpenedAt:            snapshot.OpenedAt,
+	}
+}
+
+// record reports the outcome of one call to the breaker
+func (c *ResilientClient) record(ctx context.Context, err error, retryable bool) {
+	if c.breaker == nil {
+		return
+	}
+	switch {
+	case err == nil || !retryable:
+		c.breaker.Success()
+	case ctx.Err() != nil:
+		// Cancelled by the caller: says nothing about the service
+		c.breaker.Release()
+	default:
+		c.breaker.Failure()
+	}
+}
+
+// backoff returns a full-jitter delay for the given attempt, honouring
+// Retry-After when the service sent one within MaxDelay
+func (c *ResilientClient) backoff(attempt int, err error) time.Duration {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 && statusErr.RetryAfter <= c.config.MaxDelay {
+		return statusErr.RetryAfter
+	}
+
+	ceiling := c.config.MaxDelay
+	if shift := attempt - 1; shift < 32 {
+		if d := c.config.BaseDelay << shift; d > 0 && d < ceiling {
+			ceiling = d
+		}
+	}
+	return rand.N(ceiling) + 1
+}
+
+// isRetryable reports whether a failed call may succeed if repeated
+func isRetryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError,
+			http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	// Connection refused, resets, DNS failures and client timeouts
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, context.DeadlineExceeded)
+}
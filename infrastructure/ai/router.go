@@ -0,0 +1,188 @@
+package ai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"gofiber-smart-trash/domain/ports"
+)
+
+// defaultBackendCooldown is how long a failing replica is tried only as a last resort
+const defaultBackendCooldown = 30 * time.Second
+
+// Replica is one instance of a classifier backend
+type Replica struct {
+	URL     string
+	Adapter ports.AIAdapter
+
+	unhealthyUntil atomic.Int64 // Unix nanoseconds
+}
+
+// Backend is one classifier deployment of a Router, e.g. the GPU or the CPU model
+type Backend struct {
+	Name     string
+	Replicas []*Replica
+
+	next atomic.Uint64 // Round-robin cursor over the replicas
+}
+
+// RouterConfig controls how a Router picks replicas
+type RouterConfig struct {
+	LoadBalance       bool          // Rotate calls over a backend's healthy replicas instead of preferring the first
+	UnhealthyCooldown time.Duration // How long a failing replica is skipped
+}
+
+// Router sends each classification to the first backend in configured order
+// and falls back to the next one when it fails or times out. Replicas that
+// fail or report unhealthy are skipped for a cooldown; when every replica is
+// failing they are still tried in order. The result names the backend that produced it.
+type Router struct {
+	backends []*Backend // backends[0] is the primary
+	config   RouterConfig
+}
+
+// NewRouter creates a router over backends in order of preference
+func NewRouter(backends []*Backend, config RouterConfig) (*Router, error) {
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("AI router needs at least one backend")
+	}
+	for _, backend := range backends {
+		if len(backend.Replicas) == 0 {
+			return nil, fmt.Errorf("AI backend %s has no replicas", backend.Name)
+		}
+	}
+	if config.UnhealthyCooldown <= 0 {
+		config.UnhealthyCooldown = defaultBackendCooldown
+	}
+	return &Router{
+		backends: backends,
+		config:   config,
+	}, nil
+}
+
+// ClassifyImage classifies an image by URL on the first backend that succeeds
+func (r *Router) ClassifyImage(ctx context.Context, imageURL string) (*ports.ClassificationResult, error) {
+	return r.route(ctx, func(adapter ports.AIAdapter) (*ports.ClassificationResult, error) {
+		return adapter.ClassifyImage(ctx, imageURL)
+	})
+}
+
+// ClassifyImageData classifies image data on the first backend that succeeds;
+// each backend sends the data or the URL as configured
+func (r *Router) ClassifyImageData(ctx context.Context, image ports.ImageData) (*ports.ClassificationResult, error) {
+	return r.route(ctx, func(adapter ports.AIAdapter) (*ports.ClassificationResult, error) {
+		return adapter.ClassifyImageData(ctx, image)
+	})
+}
+
+// route runs classify against replicas in call order until one succeeds or
+// fails in a way another replica would too
+func (r *Router) route(ctx context.Context, classify func(ports.AIAdapter) (*ports.ClassificationResult, error)) (*ports.ClassificationResult, error) {
+	var lastErr error
+	for i, candidate := range r.callOrder() {
+		result, err := classify(candidate.replica.Adapter)
+		if err == nil {
+			result.Backend = candidate.backend.Name
+			if i > 0 {
+				log.Printf("[AI] Classified by fallback backend %s (%s)", candidate.backend.Name, candidate.replica.URL)
+			}
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		// A rejected request says nothing about the replica's health
+		if isRetryable(err) {
+			r.markFailure(candidate.backend, candidate.replica, err)
+		}
+		lastErr = fmt.Errorf("backend %s: %w", candidate.backend.Name, err)
+		if !failsOver(err) {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
+
+// failsOver reports whether another replica should be tried after err: the
+// replica is unreachable, timed out, answered 5xx or 408/429, or cannot take
+// the image as a URL. Other 4xx responses are about the image and would be
+// the same everywhere.
+func failsOver(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= http.StatusInternalServerError || isRetryable(err)
+	}
+	return isRetryable(err) || errors.Is(err, ErrImageDataRequired)
+}
+
+// Health probes every replica, updates which ones are skipped and reports
+// whether at least one can classify
+func (r *Router) Health(ctx context.Context) (bool, error) {
+	var (
+		anyHealthy bool
+		errs       []error
+	)
+	for _, backend := range r.backends {
+		for _, replica := range backend.Replicas {
+			ok, err := replica.Adapter.Health(ctx)
+			if err == nil && !ok {
+				err = errors.New("model not ready")
+			}
+			if err != nil {
+				r.markFailure(backend, replica, err)
+				errs = append(errs, fmt.Errorf("%s (%s): %w", backend.Name, replica.URL, err))
+				continue
+			}
+			if replica.unhealthyUntil.Swap(0) != 0 {
+				log.Printf("[AI] Backend %s (%s) is healthy again", backend.Name, replica.URL)
+			}
+			anyHealthy = true
+		}
+	}
+	if anyHealthy {
+		return true, nil
+	}
+	return false, errors.Join(errs...)
+}
+
+// routeCandidate is a replica together with the backend it belongs to
+type routeCandidate struct {
+	backend *Backend
+	replica *Replica
+}
+
+// callOrder returns the healthy replicas of every backend in configured order,
+// followed by the unhealthy ones so a call is still attempted when all are failing
+func (r *Router) callOrder() []routeCandidate {
+	now := time.Now().UnixNano()
+	var healthy, unhealthy []routeCandidate
+	for _, backend := range r.backends {
+		start := 0
+		if r.config.LoadBalance {
+			start = int(backend.next.Add(1) % uint64(len(backend.Replicas)))
+		}
+		for i := range backend.Replicas {
+			replica := backend.Replicas[(start+i)%len(backend.Replicas)]
+			if replica.unhealthyUntil.Load() > now {
+				unhealthy = append(unhealthy, routeCandidate{backend, replica})
+			} else {
+				healthy = append(healthy, routeCandidate{backend, replica})
+			}
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// markFailure skips a replica for the cooldown, logging when it was healthy before
+func (r *Router) markFailure(backend *Backend, replica *Replica, err error) {
+	now := time.Now()
+	previous := replica.unhealthyUntil.Swap(now.Add(r.config.UnhealthyCooldown).UnixNano())
+	if previous <= now.UnixNano() {
+		log.Printf("[AI] Backend %s (%s) marked unhealthy for %s: %v", backend.Name, replica.URL, r.config.UnhealthyCooldown, err)
+	}
+}
@@ -0,0 +1,122 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type binRuleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewBinRuleRepository creates a new instance of BinRuleRepository
+func NewBinRuleRepository(db *gorm.DB) repositories.BinRuleRepository {
+	return &binRuleRepositoryImpl{db: db}
+}
+
+// FindActive retrieves the active rule set and its rules in evaluation order
+func (r *binRuleRepositoryImpl) FindActive(ctx context.Context) (*models.BinRuleSet, error) {
+	var ruleSet models.BinRuleSet
+	if err := r.db.WithContext(ctx).
+		Preload("Rules", orderRules).
+		Where("active = ?", true).
+		First(&ruleSet).Error; err != nil {
+		return nil, err
+	}
+	return &ruleSet, nil
+}
+
+// FindByVersion retrieves a rule set and its rules in evaluation order
+func (r *binRuleRepositoryImpl) FindByVersion(ctx context.Context, version int) (*models.BinRuleSet, error) {
+	var ruleSet models.BinRuleSet
+	if err := r.db.WithContext(ctx).
+		Preload("Rules", orderRules).
+		Where("version = ?", version).
+		First(&ruleSet).Error; err != nil {
+		return nil, err
+	}
+	return &ruleSet, nil
+}
+
+// List retrieves every rule set, newest version first
+func (r *binRuleRepositoryImpl) List(ctx context.Context) ([]models.BinRuleSet, error) {
+	var ruleSets []models.BinRuleSet
+	if err := r.db.WithContext(ctx).
+		Order("version DESC").
+		Find(&ruleSets).Error; err != nil {
+		return nil, err
+	}
+	return ruleSets, nil
+}
+
+// Create inserts a rule set with the next version number and its rules
+func (r *binRuleRepositoryImpl) Create(ctx context.Context, ruleSet *models.BinRuleSet, activate bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialise writers so two admins cannot take the same version number
+		if err := tx.Exec("LOCK TABLE bin_rule_sets IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		var latest int
+		if err := tx.Model(&models.BinRuleSet{}).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		ruleSet.Version = latest + 1
+		ruleSet.Active = false
+		if err := tx.Create(ruleSet).Error; err != nil {
+			return err
+		}
+		if !activate {
+			return nil
+		}
+		activatedAt, err := activateVersion(tx, ruleSet.Version)
+		if err != nil {
+			return err
+		}
+		ruleSet.Active = true
+		ruleSet.ActivatedAt = &activatedAt
+		return nil
+	})
+}
+
+// Activate switches the active flag to the given version
+func (r *binRuleRepositoryImpl) Activate(ctx context.Context, version int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("LOCK TABLE bin_rule_sets IN EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+		_, err := activateVersion(tx, version)
+		return err
+	})
+}
+
+// activateVersion deactivates every other version and activates the given one
+func activateVersion(tx *gorm.DB, version int) (time.Time, error) {
+	now := time.Now()
+	result := tx.Model(&models.BinRuleSet{}).
+		Where("version = ?", version).
+		Updates(map[string]interface{}{
+			"active":       true,
+			"activated_at": now,
+		})
+	if result.Error != nil {
+		return now, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return now, gorm.ErrRecordNotFound
+	}
+	return now, tx.Model(&models.BinRuleSet{}).
+		Where("version <> ? AND active = ?", version, true).
+		Update("active", false).Error
+}
+
+// orderRules loads rules in evaluation order
+func orderRules(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
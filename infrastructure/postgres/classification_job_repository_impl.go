@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type classificationJobRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewClassificationJobRepository creates a new instance of ClassificationJobRepository
+func NewClassificationJobRepository(db *gorm.DB) repositories.ClassificationJobRepository {
+	return &classificationJobRepositoryImpl{db: db}
+}
+
+// Enqueue inserts a new classification job
+func (r *classificationJobRepositoryImpl) Enqueue(ctx context.Context, job *models.ClassificationJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+// ClaimNext locks the oldest due job with SKIP LOCKED so concurrent workers never get the same job
+func (r *classificationJobRepositoryImpl) ClaimNext(ctx context.Context, staleBefore time.Time) (*models.ClassificationJob, error) {
+	var job models.ClassificationJob
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("(status = ? AND run_at <= ?) OR (status = ? AND locked_at < ?)",
+				models.ClassificationJobQueued, now, models.ClassificationJobRunning, staleBefore).
+			Order("run_at ASC").
+			First(&job).Error; err != nil {
+			return err
+		}
+
+		job.Status = models.ClassificationJobRunning
+		job.LockedAt = &now
+		job.Attempts++
+		return tx.Model(&job).Updates(map[string]interface{}{
+			"status":    job.Status,
+			"locked_at": job.LockedAt,
+			"attempts":  job.Attempts,
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// Complete marks a job as done
+func (r *classificationJobRepositoryImpl) Complete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ClassificationJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.ClassificationJobDone,
+			"locked_at":  nil,
+			"last_error": "",
+		}).Error
+}
+
+// Retry returns a job to the queue after a failed attempt
+func (r *classificationJobRepositoryImpl) Retry(ctx context.Context, id uuid.UUID, runAt time.Time, lastError string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ClassificationJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.ClassificationJobQueued,
+			"run_at":     runAt,
+			"locked_at":  nil,
+			"last_error": lastError,
+		}).Error
+}
+
+// Fail marks a job as permanently failed
+func (r *classificationJobRepositoryImpl) Fail(ctx context.Context, id uuid.UUID, lastError string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.ClassificationJob{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":     models.ClassificationJobFailed,
+			"locked_at":  nil,
+			"last_error": lastError,
+		}).Error
+}
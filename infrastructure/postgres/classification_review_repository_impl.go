@@ -0,0 +1,79 @@
+package postgres
+
+import (
+	"context"
+
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type classificationReviewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewClassificationReviewRepository creates a new instance of ClassificationReviewRepository
+func NewClassificationReviewRepository(db *gorm.DB) repositories.ClassificationReviewRepository {
+	return &classificationReviewRepositoryImpl{db: db}
+}
+
+// FindPending retrieves the review queue with pagination
+func (r *classificationReviewRepositoryImpl) FindPending(ctx context.Context, filter repositories.ReviewFilter) ([]models.TrashRecord, int64, error) {
+	var trashList []models.TrashRecord
+	var total int64
+
+	query := r.db.WithContext(ctx).
+		Model(&models.TrashRecord{}).
+		Where("review_status = ?", models.ReviewPending)
+	if filter.DeviceID != "" {
+		query = query.Where("device_id = ?", filter.DeviceID)
+	}
+	if filter.Reason != "" {
+		query = query.Where("review_reason = ?", filter.Reason)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.
+		Order("created_at ASC").
+		Limit(filter.Limit).
+		Offset(filter.Offset).
+		Find(&trashList).Error; err != nil {
+		return nil, 0, err
+	}
+	return trashList, total, nil
+}
+
+// Submit inserts the review and writes the corrected label onto the trash record
+func (r *classificationReviewRepositoryImpl) Submit(ctx context.Context, trash *models.TrashRecord, review *models.ClassificationReview) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(review).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.TrashRecord{}).
+			Where("id = ?", trash.ID).
+			Updates(map[string]interface{}{
+				"category":      trash.Category,
+				"sub_category":  trash.SubCategory,
+				"bin_number":    trash.BinNumber,
+				"bin_label":     trash.BinLabel,
+				"review_status": trash.ReviewStatus,
+				"review_reason": trash.ReviewReason,
+			}).Error
+	})
+}
+
+// FindByTrashID retrieves the reviews of a trash record
+func (r *classificationReviewRepositoryImpl) FindByTrashID(ctx context.Context, trashID uuid.UUID) ([]models.ClassificationReview, error) {
+	var reviews []models.ClassificationReview
+	if err := r.db.WithContext(ctx).
+		Where("trash_record_id = ?", trashID).
+		Order("created_at ASC").
+		Find(&reviews).Error; err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
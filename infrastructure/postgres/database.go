@@ -38,5 +38,13 @@ func NewDatabase(config DatabaseConfig) (*gorm.DB, error) {
 func Migrate(db *gorm.DB) error {
 	return db.AutoMigrate(
 		&models.TrashRecord{},
+		&models.UploadSession{},
+		&models.ClassificationJob{},
+		&models.Classification{},
+		&models.Detection{},
+		&models.ClassificationReview{},
+		&models.ShadowClassification{},
+		&models.BinRuleSet{},
+		&models.BinRule{},
 	)
 }
\ No newline at end of file
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/repositories"
+
+	"gorm.io/gorm"
+)
+
+type shadowClassificationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewShadowClassificationRepository creates a new instance of ShadowClassificationRepository
+func NewShadowClassificationRepository(db *gorm.DB) repositories.ShadowClassificationRepository {
+	return &shadowClassificationRepositoryImpl{db: db}
+}
+
+// Create inserts a shadow result
+func (r *shadowClassificationRepositoryImpl) Create(ctx context.Context, shadow *models.ShadowClassification) error {
+	return r.db.WithContext(ctx).Create(shadow).Error
+}
+
+// CountPairs groups successful shadow results by primary and shadow category
+func (r *shadowClassificationRepositoryImpl) CountPairs(ctx context.Context, filter repositories.ShadowFilter) ([]repositories.ShadowPairCount, error) {
+	var pairs []repositories.ShadowPairCount
+	err := shadowQuery(r.db.WithContext(ctx).Model(&models.ShadowClassification{}), filter).
+		Select("primary_category, category, COUNT(*) AS count").
+		Where("COALESCE(error, '') = ''").
+		Group("primary_category, category").
+		Order("primary_category, category").
+		Scan(&pairs).Error
+	if err != nil {
+		return nil, err
+	}
+	return pairs, nil
+}
+
+// CountFailed counts shadow results recorded with an error
+func (r *shadowClassificationRepositoryImpl) CountFailed(ctx context.Context, filter repositories.ShadowFilter) (int64, error) {
+	var count int64
+	err := shadowQuery(r.db.WithContext(ctx).Model(&models.ShadowClassification{}), filter).
+		Where("COALESCE(error, '') <> ''").
+		Count(&count).Error
+	return count, err
+}
+
+// shadowQuery applies a shadow filter to a query
+func shadowQuery(query *gorm.DB, filter repositories.ShadowFilter) *gorm.DB {
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.ModelVersion != "" {
+		query = query.Where("model_version = ?", filter.ModelVersion)
+	}
+	return query
+}
@@ -2,12 +2,14 @@ package postgres
 
 import (
 	"context"
+	"time"
 
 	"gofiber-smart-trash/domain/models"
 	"gofiber-smart-trash/domain/repositories"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type trashRepositoryImpl struct {
@@ -61,3 +63,224 @@ func (r *trashRepositoryImpl) FindAll(ctx context.Context, filter repositories.T
 
 	return trashList, total, nil
 }
+
+// UpdateImageVariants stores the keys of the resized image variants
+func (r *trashRepositoryImpl) UpdateImageVariants(ctx context.Context, id uuid.UUID, thumbnailKey, mediumKey string) error {
+	return r.db.WithContext(ctx).
+		Model(&models.TrashRecord{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"thumbnail_key": thumbnailKey,
+			"medium_key":    mediumKey,
+		}).Error
+}
+
+// CreateWithClassificationJob inserts a trash record and enqueues its classification atomically
+func (r *trashRepositoryImpl) CreateWithClassificationJob(ctx context.Context, trash *models.TrashRecord, job *models.ClassificationJob) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(trash).Error; err != nil {
+			return err
+		}
+		return tx.Create(job).Error
+	})
+}
+
+// UpdateClassification saves the AI classification result of a trash record
+// together with any new classification history entries
+func (r *trashRepositoryImpl) UpdateClassification(ctx context.Context, trash *models.TrashRecord) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.TrashRecord{}).
+			Where("id = ?", trash.ID).
+			Updates(map[string]interface{}{
+				"classification_status": trash.ClassificationStatus,
+				"category":              trash.Category,
+				"sub_category":          trash.SubCategory,
+				"confidence":            trash.Confidence,
+				"bin_number":            trash.BinNumber,
+				"bin_label":             trash.BinLabel,
+				"classify_error":        trash.ClassifyError,
+				"classified_at":         trash.ClassifiedAt,
+				"review_status":         trash.ReviewStatus,
+				"review_reason":         trash.ReviewReason,
+			}).Error; err != nil {
+			return err
+		}
+		if len(trash.Classifications) == 0 {
+			return nil
+		}
+		// Entries loaded from the database are already there
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&trash.Classifications).Error
+	})
+}
+
+// FindClassifications retrieves the classification history of a trash record
+func (r *trashRepositoryImpl) FindClassifications(ctx context.Context, trashID uuid.UUID) ([]models.Classification, error) {
+	var classifications []models.Classification
+	if err := r.db.WithContext(ctx).
+		Preload("Detections", func(db *gorm.DB) *gorm.DB {
+			return db.Order("confidence DESC")
+		}).
+		Where("trash_record_id = ?", trashID).
+		Order("created_at ASC").
+		Find(&classifications).Error; err != nil {
+		return nil, err
+	}
+	return classifications, nil
+}
+
+// FindRecentByDevice retrieves a device's trash records created since the given time
+func (r *trashRepositoryImpl) FindRecentByDevice(ctx context.Context, deviceID string, since time.Time) ([]models.TrashRecord, error) {
+	var trashList []models.TrashRecord
+	if err := r.db.WithContext(ctx).
+		Where("device_id = ? AND created_at >= ?", deviceID, since).
+		Order("created_at DESC").
+		Find(&trashList).Error; err != nil {
+		return nil, err
+	}
+	return trashList, nil
+}
+
+// FindBatchAfterID retrieves the next page of trash records in ID order for batch jobs
+func (r *trashRepositoryImpl) FindBatchAfterID(ctx context.Context, afterID uuid.UUID, limit int) ([]models.TrashRecord, error) {
+	var trashList []models.TrashRecord
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&trashList).Error; err != nil {
+		return nil, err
+	}
+	return trashList, nil
+}
+
+// CountForReclassify counts the trash records selected by a reclassify filter
+func (r *trashRepositoryImpl) CountForReclassify(ctx context.Context, filter repositories.ReclassifyFilter) (int64, error) {
+	var total int64
+	err := reclassifyQuery(r.db.WithContext(ctx).Model(&models.TrashRecord{}), filter).Count(&total).Error
+	return total, err
+}
+
+// FindForReclassify retrieves the next page of trash records selected by a reclassify filter in ID order
+func (r *trashRepositoryImpl) FindForReclassify(ctx context.Context, filter repositories.ReclassifyFilter, afterID uuid.UUID, limit int) ([]models.TrashRecord, error) {
+	var trashList []models.TrashRecord
+	if err := reclassifyQuery(r.db.WithContext(ctx), filter).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&trashList).Error; err != nil {
+		return nil, err
+	}
+	return trashList, nil
+}
+
+// FindForExport retrieves the next page of labelled trash records for a dataset export in ID order
+func (r *trashRepositoryImpl) FindForExport(ctx context.Context, filter repositories.DatasetFilter, afterID uuid.UUID, limit int) ([]models.TrashRecord, error) {
+	query := r.db.WithContext(ctx).
+		Preload("Classifications", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		Preload("Classifications.Detections").
+		Where("classification_status = ? AND COALESCE(category, '') <> ''", models.ClassificationClassified).
+		Where("image_key <> ''")
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.MinConfidence > 0 {
+		query = query.Where("(review_status = ? OR confidence >= ?)", models.ReviewReviewed, filter.MinConfidence)
+	}
+	if len(filter.ReviewStatuses) > 0 {
+		query = query.Where("review_status IN ?", filter.ReviewStatuses)
+	}
+
+	var trashList []models.TrashRecord
+	if err := query.
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&trashList).Error; err != nil {
+		return nil, err
+	}
+	return trashList, nil
+}
+
+// reclassifyQuery applies a reclassify filter to a query
+func reclassifyQuery(query *gorm.DB, filter repositories.ReclassifyFilter) *gorm.DB {
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", *filter.CreatedFrom)
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at < ?", *filter.CreatedTo)
+	}
+	if filter.DeviceID != "" {
+		query = query.Where("device_id = ?", filter.DeviceID)
+	}
+	// Neither column has a default, so older rows may hold NULL
+	switch {
+	case filter.EmptyCategory && filter.HasError:
+		query = query.Where("(COALESCE(category, '') = '' OR COALESCE(classify_error, '') <> '')")
+	case filter.EmptyCategory:
+		query = query.Where("COALESCE(category, '') = ''")
+	case filter.HasError:
+		query = query.Where("COALESCE(classify_error, '') <> ''")
+	}
+	return query
+}
+
+// UpdateImageLocations updates image_url and image_key for each record in a single transaction
+func (r *trashRepositoryImpl) UpdateImageLocations(ctx context.Context, locations []repositories.ImageLocation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, location := range locations {
+			if err := tx.Unscoped().
+				Model(&models.TrashRecord{}).
+				Where("id = ?", location.ID).
+				Updates(map[string]interface{}{
+					"image_url": location.ImageURL,
+					"image_key": location.ImageKey,
+				}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// FindExistingImageURLs returns which of the given image URLs belong to a trash record
+func (r *trashRepositoryImpl) FindExistingImageURLs(ctx context.Context, imageURLs []string) ([]string, error) {
+	var existing []string
+	if len(imageURLs) == 0 {
+		return existing, nil
+	}
+
+	// Unscoped so images of soft-deleted records are kept as evidence
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.TrashRecord{}).
+		Where("image_url IN ?", imageURLs).
+		Distinct().
+		Pluck("image_url", &existing).Error; err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+// FindExistingImageKeys returns which of the given object keys belong to a trash record
+func (r *trashRepositoryImpl) FindExistingImageKeys(ctx context.Context, imageKeys []string) ([]string, error) {
+	var existing []string
+	if len(imageKeys) == 0 {
+		return existing, nil
+	}
+
+	if err := r.db.WithContext(ctx).
+		Unscoped().
+		Model(&models.TrashRecord{}).
+		Where("image_key IN ?", imageKeys).
+		Distinct().
+		Pluck("image_key", &existing).Error; err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
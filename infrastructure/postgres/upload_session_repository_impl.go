@@ -0,0 +1,92 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"gofiber-smart-trash/domain/models"
+	"gofiber-smart-trash/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type uploadSessionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewUploadSessionRepository creates a new instance of UploadSessionRepository
+func NewUploadSessionRepository(db *gorm.DB) repositories.UploadSessionRepository {
+	return &uploadSessionRepositoryImpl{db: db}
+}
+
+// Create inserts a new upload session into the database
+func (r *uploadSessionRepositoryImpl) Create(ctx context.Context, session *models.UploadSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+// FindByID retrieves an upload session by its ID
+func (r *uploadSessionRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*models.UploadSession, error) {
+	var session models.UploadSession
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// FindByObjectKey retrieves the upload session issued for an object key
+func (r *uploadSessionRepositoryImpl) FindByObjectKey(ctx context.Context, objectKey string) (*models.UploadSession, error) {
+	var session models.UploadSession
+	if err := r.db.WithContext(ctx).Where("object_key = ?", objectKey).First(&session).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Renew moves the expiry of an unused upload session and marks it pending again
+func (r *uploadSessionRepositoryImpl) Renew(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&models.UploadSession{}).
+		Where("id = ? AND status <> ?", id, models.UploadSessionUsed).
+		Updates(map[string]interface{}{
+			"status":     models.UploadSessionPending,
+			"expires_at": expiresAt,
+		}).Error
+}
+
+// Claim marks a pending upload session as used by the given trash record
+func (r *uploadSessionRepositoryImpl) Claim(ctx context.Context, id uuid.UUID, trashRecordID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.UploadSession{}).
+		Where("id = ? AND status = ?", id, models.UploadSessionPending).
+		Updates(map[string]interface{}{
+			"status":          models.UploadSessionUsed,
+			"used_at":         time.Now(),
+			"trash_record_id": trashRecordID,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// Release returns a used upload session to pending
+func (r *uploadSessionRepositoryImpl) Release(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Model(&models.UploadSession{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":          models.UploadSessionPending,
+			"used_at":         nil,
+			"trash_record_id": nil,
+		}).Error
+}
+
+// ExpirePending marks stale pending upload sessions as expired
+func (r *uploadSessionRepositoryImpl) ExpirePending(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&models.UploadSession{}).
+		Where("status = ? AND expires_at < ?", models.UploadSessionPending, before).
+		Update("status", models.UploadSessionExpired)
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,252 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"gofiber-smart-trash/domain/ports"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+	"google.golang.org/api/option"
+)
+
+// GCSConfig holds the settings for a Google Cloud Storage backend
+type GCSConfig struct {
+	CredentialsPath string // Service account JSON; empty uses Application Default Credentials
+	Bucket          string
+	PublicURL       string // Optional; defaults to https://storage.googleapis.com/{bucket}
+	EmulatorHost    string // Optional emulator endpoint (e.g. http://localhost:4443 for fake-gcs-server)
+}
+
+// GCSStorageAdapter implements StorageAdapter for Google Cloud Storage
+type GCSStorageAdapter struct {
+	client    *storage.Client
+	bucket    string
+	publicURL string
+	emulator  string
+}
+
+// NewGCSStorageAdapter creates a new GCS storage adapter
+func NewGCSStorageAdapter(cfg GCSConfig) (*GCSStorageAdapter, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("GCS bucket is required")
+	}
+
+	emulator := strings.TrimSuffix(cfg.EmulatorHost, "/")
+	if emulator != "" && !strings.Contains(emulator, "://") {
+		emulator = "http://" + emulator
+	}
+
+	var opts []option.ClientOption
+	switch {
+	case emulator != "":
+		// Emulators accept any credentials, so skip auth entirely
+		opts = append(opts,
+			option.WithEndpoint(emulator+"/storage/v1/"),
+			option.WithoutAuthentication(),
+		)
+	case cfg.CredentialsPath != "":
+		opts = append(opts, option.WithCredentialsFile(cfg.CredentialsPath))
+	}
+
+	client, err := storage.NewClient(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCS client: %w", err)
+	}
+
+	publicURL := strings.TrimSuffix(cfg.PublicURL, "/")
+	if publicURL == "" {
+		if emulator != "" {
+			publicURL = fmt.Sprintf("%s/%s", emulator, cfg.Bucket)
+		} else {
+			publicURL = fmt.Sprintf("https://storage.googleapis.com/%s", cfg.Bucket)
+		}
+	}
+
+	return &GCSStorageAdapter{
+		client:    client,
+		bucket:    cfg.Bucket,
+		publicURL: publicURL,
+		emulator:  emulator,
+	}, nil
+}
+
+// GeneratePresignedUploadURL generates a V4 signed URL for uploading objects to GCS.
+// The size limit is enforced through the signed x-goog-content-length-range header.
+func (a *GCSStorageAdapter) GeneratePresignedUploadURL(ctx context.Context, key string, opts ports.UploadOptions) (*ports.PresignedURLResponse, error) {
+	if opts.ChecksumSHA256 != "" {
+		return nil, fmt.Errorf("%w: GCS does not support SHA-256, use Content-MD5 instead", ports.ErrUnsupportedChecksum)
+	}
+
+	headers := make(map[string]string)
+	if opts.MaxBytes > 0 {
+		headers["x-goog-content-length-range"] = fmt.Sprintf("0,%d", opts.MaxBytes)
+	}
+
+	signOpts := &storage.SignedURLOptions{
+		Scheme:      storage.SigningSchemeV4,
+		Method:      http.MethodPut,
+		ContentType: opts.ContentType,
+		Expires:     time.Now().Add(opts.Expiry),
+	}
+	for name, value := range headers {
+		signOpts.Headers = append(signOpts.Headers, fmt.Sprintf("%s:%s", name, value))
+	}
+	if opts.ContentMD5 != "" {
+		signOpts.MD5 = opts.ContentMD5
+		headers["Content-MD5"] = opts.ContentMD5
+	}
+	a.applyEmulatorSigning(signOpts)
+
+	uploadURL, err := a.client.Bucket(a.bucket).SignedURL(key, signOpts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign GCS upload URL: %w", err)
+	}
+	headers["Content-Type"] = opts.ContentType
+
+	return &ports.PresignedURLResponse{
+		UploadURL: uploadURL,
+		Method:    http.MethodPut,
+		Headers:   headers,
+		PublicURL: a.GeneratePublicURL(key),
+		ExpiresIn: int64(opts.Expiry.Seconds()),
+	}, nil
+}
+
+// GeneratePublicURL generates a public URL for accessing an object
+func (a *GCSStorageAdapter) GeneratePublicURL(key string) string {
+	return fmt.Sprintf("%s/%s", a.publicURL, key)
+}
+
+// GeneratePresignedDownloadURL generates a V4 signed GET URL for reading a private object
+func (a *GCSStorageAdapter) GeneratePresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	opts := &storage.SignedURLOptions{
+		Scheme:  storage.SigningSchemeV4,
+		Method:  http.MethodGet,
+		Expires: time.Now().Add(expiry),
+	}
+	a.applyEmulatorSigning(opts)
+
+	signedURL, err := a.client.Bucket(a.bucket).SignedURL(key, opts)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign GCS download URL: %w", err)
+	}
+	return signedURL, nil
+}
+
+// DeleteObject removes an object from GCS
+func (a *GCSStorageAdapter) DeleteObject(ctx context.Context, key string) error {
+	err := a.client.Bucket(a.bucket).Object(key).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		// Match S3 semantics: deleting a missing object is not an error
+		return nil
+	}
+	return err
+}
+
+// PutObject uploads an object to GCS from the server side
+func (a *GCSStorageAdapter) PutObject(ctx context.Context, key string, body io.Reader, contentType string) error {
+	// Cancelling the context aborts the upload instead of committing a partial object
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	writer := a.client.Bucket(a.bucket).Object(key).NewWriter(ctx)
+	writer.ContentType = contentType
+	if _, err := io.Copy(writer, body); err != nil {
+		cancel()
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	return nil
+}
+
+// GetObject opens an object in GCS for reading
+func (a *GCSStorageAdapter) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	reader, err := a.client.Bucket(a.bucket).Object(key).NewReader(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, ports.ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	return reader, nil
+}
+
+// StatObject returns metadata for an object in GCS
+func (a *GCSStorageAdapter) StatObject(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+	attrs, err := a.client.Bucket(a.bucket).Object(key).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil, ports.ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get object attributes: %w", err)
+	}
+
+	return &ports.ObjectInfo{
+		Key:          key,
+		Size:         attrs.Size,
+		ContentType:  attrs.ContentType,
+		ETag:         strings.Trim(attrs.Etag, `"`),
+		LastModified: attrs.Updated,
+	}, nil
+}
+
+// ListObjects walks all objects under a prefix in GCS
+func (a *GCSStorageAdapter) ListObjects(ctx context.Context, prefix string, fn func(ports.ObjectInfo) error) error {
+	it := a.client.Bucket(a.bucket).Objects(ctx, &storage.Query{Prefix: prefix})
+	for {
+		attrs, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		if err := fn(ports.ObjectInfo{
+			Key:          attrs.Name,
+			Size:         attrs.Size,
+			ContentType:  attrs.ContentType,
+			ETag:         strings.Trim(attrs.Etag, `"`),
+			LastModified: attrs.Updated,
+		}); err != nil {
+			return err
+		}
+	}
+}
+
+// CopyObject copies an object within the GCS bucket
+func (a *GCSStorageAdapter) CopyObject(ctx context.Context, srcKey, dstKey string) error {
+	bucket := a.client.Bucket(a.bucket)
+	if _, err := bucket.Object(dstKey).CopierFrom(bucket.Object(srcKey)).Run(ctx); err != nil {
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	return nil
+}
+
+// Close releases the underlying GCS client
+func (a *GCSStorageAdapter) Close() error {
+	return a.client.Close()
+}
+
+// applyEmulatorSigning points signed URLs at the emulator and signs them with a
+// placeholder key, since emulators do not verify signatures
+func (a *GCSStorageAdapter) applyEmulatorSigning(opts *storage.SignedURLOptions) {
+	if a.emulator == "" {
+		return
+	}
+	scheme, host, _ := strings.Cut(a.emulator, "://")
+	opts.Hostname = host
+	opts.Insecure = scheme == "http"
+	opts.GoogleAccessID = "emulator@localhost"
+	opts.SignBytes = func(b []byte) ([]byte, error) {
+		return []byte("emulator"), nil
+	}
+}
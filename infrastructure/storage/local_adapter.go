@@ -0,0 +1,352 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"gofiber-smart-trash/domain/ports"
+)
+
+// LocalConfig holds the settings for the local filesystem backend
+type LocalConfig struct {
+	BasePath string // Directory where objects are stored
+	BaseURL  string // Externally reachable URL of this API (e.g. http://192.168.1.10:8080)
+	Secret   string // HMAC secret for signing upload and download URLs
+	Private  bool   // Require signed URLs for GET /files/*
+}
+
+// LocalStorageAdapter implements StorageAdapter on the local filesystem.
+// Uploads and downloads are served by the API itself under /files.
+type LocalStorageAdapter struct {
+	basePath string
+	baseURL  string
+	secret   []byte
+	private  bool
+}
+
+// NewLocalStorageAdapter creates a new local filesystem storage adapter
+func NewLocalStorageAdapter(cfg LocalConfig) (*LocalStorageAdapter, error) {
+	if cfg.BasePath == "" {
+		return nil, fmt.Errorf("local storage path is required")
+	}
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("local storage base URL is required")
+	}
+
+	basePath, err := filepath.Abs(cfg.BasePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid local storage path: %w", err)
+	}
+	if err := os.MkdirAll(basePath, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local storage directory: %w", err)
+	}
+
+	secret := []byte(cfg.Secret)
+	if len(secret) == 0 {
+		// Random secret means signed URLs do not survive a restart, which is fine for dev
+		log.Println("Warning: LOCAL_STORAGE_SECRET not set, generating a random secret")
+		secret = make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+	}
+
+	return &LocalStorageAdapter{
+		basePath: basePath,
+		baseURL:  strings.TrimSuffix(cfg.BaseURL, "/"),
+		secret:   secret,
+		private:  cfg.Private,
+	}, nil
+}
+
+// GeneratePresignedUploadURL generates an HMAC-signed URL for uploading to this API.
+// All upload constraints are carried in the query string and covered by the signature.
+func (a *LocalStorageAdapter) GeneratePresignedUploadURL(ctx context.Context, key string, opts ports.UploadOptions) (*ports.PresignedURLResponse, error) {
+	if _, err := a.ObjectPath(key); err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(time.Now().Add(opts.Expiry).Unix(), 10))
+	if opts.ContentType != "" {
+		query.Set("content_type", opts.ContentType)
+	}
+	if opts.MaxBytes > 0 {
+		query.Set("max_bytes", strconv.FormatInt(opts.MaxBytes, 10))
+	}
+	if opts.ContentMD5 != "" {
+		query.Set("content_md5", opts.ContentMD5)
+	}
+	if opts.ChecksumSHA256 != "" {
+		query.Set("checksum_sha256", opts.ChecksumSHA256)
+	}
+	query.Set("signature", a.sign(http.MethodPut, key, query))
+
+	headers := map[string]string{}
+	if opts.ContentType != "" {
+		headers["Content-Type"] = opts.ContentType
+	}
+
+	return &ports.PresignedURLResponse{
+		UploadURL: fmt.Sprintf("%s/files/upload/%s?%s", a.baseURL, key, query.Encode()),
+		Method:    http.MethodPut,
+		Headers:   headers,
+		PublicURL: a.GeneratePublicURL(key),
+		ExpiresIn: int64(opts.Expiry.Seconds()),
+	}, nil
+}
+
+// GeneratePublicURL generates a URL for reading an object through this API
+func (a *LocalStorageAdapter) GeneratePublicURL(key string) string {
+	return fmt.Sprintf("%s/files/%s", a.baseURL, key)
+}
+
+// GeneratePresignedDownloadURL generates an HMAC-signed URL for reading an object through this API
+func (a *LocalStorageAdapter) GeneratePresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(time.Now().Add(expiry).Unix(), 10))
+	query.Set("signature", a.sign(http.MethodGet, key, query))
+	return fmt.Sprintf("%s?%s", a.GeneratePublicURL(key), query.Encode()), nil
+}
+
+// VerifyDownload checks a signed read URL. Public stores accept any request.
+func (a *LocalStorageAdapter) VerifyDownload(key string, query url.Values) error {
+	if !a.private {
+		return nil
+	}
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid expires")
+	}
+	if time.Now().Unix() > expires {
+		return fmt.Errorf("download URL has expired")
+	}
+	if !hmac.Equal([]byte(a.sign(http.MethodGet, key, query)), []byte(query.Get("signature"))) {
+		return fmt.Errorf("invalid download signature")
+	}
+	return nil
+}
+
+// DeleteObject removes an object from the local filesystem
+func (a *LocalStorageAdapter) DeleteObject(ctx context.Context, key string) error {
+	path, err := a.ObjectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// GetObject opens an object on the local filesystem for reading
+func (a *LocalStorageAdapter) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := a.ObjectPath(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ports.ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// StatObject returns metadata for an object on the local filesystem.
+// Content type is sniffed from the file and the ETag is its MD5, like S3.
+func (a *LocalStorageAdapter) StatObject(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+	path, err := a.ObjectPath(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ports.ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	hash := md5.New()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(file, head)
+	hash.Write(head[:n])
+	if _, err := io.Copy(hash, file); err != nil {
+		return nil, fmt.Errorf("failed to read object: %w", err)
+	}
+
+	return &ports.ObjectInfo{
+		Key:          key,
+		Size:         stat.Size(),
+		ContentType:  http.DetectContentType(head[:n]),
+		ETag:         hex.EncodeToString(hash.Sum(nil)),
+		LastModified: stat.ModTime(),
+	}, nil
+}
+
+// ListObjects walks all objects under a prefix on the local filesystem
+func (a *LocalStorageAdapter) ListObjects(ctx context.Context, prefix string, fn func(ports.ObjectInfo) error) error {
+	err := filepath.WalkDir(a.basePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		// Skip directories and in-flight temp files from PutObject
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(a.basePath, path)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(ports.ObjectInfo{
+			Key:          key,
+			Size:         info.Size(),
+			LastModified: info.ModTime(),
+		})
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// CopyObject copies an object to a new key on the local filesystem
+func (a *LocalStorageAdapter) CopyObject(ctx context.Context, srcKey, dstKey string) error {
+	src, err := a.GetObject(ctx, srcKey)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return a.PutObject(ctx, dstKey, src, "")
+}
+
+// VerifyUpload checks that an upload URL was issued by us, has not expired,
+// and that the body satisfies the constraints it was signed with
+func (a *LocalStorageAdapter) VerifyUpload(key string, query url.Values, contentType string, body []byte) error {
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("missing or invalid expires")
+	}
+	if time.Now().Unix() > expires {
+		return fmt.Errorf("upload URL has expired")
+	}
+	if !hmac.Equal([]byte(a.sign(http.MethodPut, key, query)), []byte(query.Get("signature"))) {
+		return fmt.Errorf("invalid upload signature")
+	}
+
+	if expected := query.Get("content_type"); expected != "" && contentType != expected {
+		return fmt.Errorf("content type must be %s", expected)
+	}
+	if maxBytes := query.Get("max_bytes"); maxBytes != "" {
+		limit, _ := strconv.ParseInt(maxBytes, 10, 64)
+		if int64(len(body)) > limit {
+			return fmt.Errorf("upload exceeds limit of %d bytes", limit)
+		}
+	}
+	if expected := query.Get("content_md5"); expected != "" {
+		sum := md5.Sum(body)
+		if base64.StdEncoding.EncodeToString(sum[:]) != expected {
+			return fmt.Errorf("content MD5 mismatch")
+		}
+	}
+	if expected := query.Get("checksum_sha256"); expected != "" {
+		sum := sha256.Sum256(body)
+		if base64.StdEncoding.EncodeToString(sum[:]) != expected {
+			return fmt.Errorf("SHA-256 checksum mismatch")
+		}
+	}
+	return nil
+}
+
+// PutObject writes an object to the local filesystem
+func (a *LocalStorageAdapter) PutObject(ctx context.Context, key string, body io.Reader, contentType string) error {
+	path, err := a.ObjectPath(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// Write to a temp file first so readers never see a partial image
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write object: %w", err)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+// ObjectPath resolves an object key to a path inside the storage directory
+func (a *LocalStorageAdapter) ObjectPath(key string) (string, error) {
+	cleaned := filepath.Clean("/" + key)
+	if cleaned == "/" {
+		return "", fmt.Errorf("invalid object key: %q", key)
+	}
+	path := filepath.Join(a.basePath, cleaned)
+	if !strings.HasPrefix(path, a.basePath+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid object key: %q", key)
+	}
+	return path, nil
+}
+
+// sign computes the HMAC signature over the method, key and every signed query parameter.
+// Including the method keeps a download signature from being replayed as an upload.
+func (a *LocalStorageAdapter) sign(method, key string, query url.Values) string {
+	mac := hmac.New(sha256.New, a.secret)
+	fmt.Fprintf(mac, "%s\n%s", method, key)
+	for _, name := range []string{"expires", "content_type", "max_bytes", "content_md5", "checksum_sha256"} {
+		fmt.Fprintf(mac, "\n%s=%s", name, query.Get(name))
+	}
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,266 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"gofiber-smart-trash/domain/ports"
+)
+
+// defaultUnhealthyCooldown is how long a replica is skipped for reads after a failure
+const defaultUnhealthyCooldown = 30 * time.Second
+
+// Replica is one storage backend of a MirroredStorageAdapter
+type Replica struct {
+	Name    string
+	Adapter ports.StorageAdapter
+
+	unhealthyUntil atomic.Int64 // Unix nanoseconds
+}
+
+// MirroredStorageAdapter writes every object to a primary and one or more
+// secondary adapters and reads from the first healthy one. Devices upload to
+// the primary through presigned URLs; Repair copies those objects to the
+// secondaries. The primary is the source of truth and is never repaired from
+// a secondary.
+type MirroredStorageAdapter struct {
+	replicas []*Replica // replicas[0] is the primary
+	cooldown time.Duration
+}
+
+// RepairReport summarises a replica reconciliation run
+type RepairReport struct {
+	Scanned  int            `json:"scanned"`  // Objects on the primary
+	Repaired map[string]int `json:"repaired"` // Objects copied into each replica
+	Failed   int            `json:"failed"`
+}
+
+// NewMirroredStorageAdapter creates a mirrored adapter. The first replica is the
+// primary: it issues upload URLs and the public URLs stored on records.
+func NewMirroredStorageAdapter(replicas []*Replica, unhealthyCooldown time.Duration) (*MirroredStorageAdapter, error) {
+	if len(replicas) < 2 {
+		return nil, fmt.Errorf("mirrored storage needs a primary and at least one secondary")
+	}
+	if unhealthyCooldown <= 0 {
+		unhealthyCooldown = defaultUnhealthyCooldown
+	}
+	return &MirroredStorageAdapter{
+		replicas: replicas,
+		cooldown: unhealthyCooldown,
+	}, nil
+}
+
+// GeneratePresignedUploadURL issues the upload URL for the primary
+func (a *MirroredStorageAdapter) GeneratePresignedUploadURL(ctx context.Context, key string, opts ports.UploadOptions) (*ports.PresignedURLResponse, error) {
+	return a.primary().Adapter.GeneratePresignedUploadURL(ctx, key, opts)
+}
+
+// GeneratePublicURL returns the primary's public URL
+func (a *MirroredStorageAdapter) GeneratePublicURL(key string) string {
+	return a.primary().Adapter.GeneratePublicURL(key)
+}
+
+// GeneratePresignedDownloadURL signs a read URL on the first healthy replica
+func (a *MirroredStorageAdapter) GeneratePresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	var lastErr error
+	for _, replica := range a.readOrder() {
+		signedURL, err := replica.Adapter.GeneratePresignedDownloadURL(ctx, key, expiry)
+		if err == nil {
+			return signedURL, nil
+		}
+		a.markFailure(replica, err)
+		lastErr = err
+	}
+	return "", lastErr
+}
+
+// DeleteObject deletes the object from every replica. Only a primary failure is returned.
+func (a *MirroredStorageAdapter) DeleteObject(ctx context.Context, key string) error {
+	return a.writeAll(ctx, "delete", key, func(adapter ports.StorageAdapter) error {
+		return adapter.DeleteObject(ctx, key)
+	})
+}
+
+// PutObject writes the object to every replica. The body is buffered so each
+// replica gets a full copy; only a primary failure is returned.
+func (a *MirroredStorageAdapter) PutObject(ctx context.Context, key string, body io.Reader, contentType string) error {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return fmt.Errorf("failed to read object body: %w", err)
+	}
+	return a.writeAll(ctx, "put", key, func(adapter ports.StorageAdapter) error {
+		return adapter.PutObject(ctx, key, bytes.NewReader(data), contentType)
+	})
+}
+
+// GetObject reads from the first healthy replica that has the object
+func (a *MirroredStorageAdapter) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	var lastErr error
+	for _, replica := range a.readOrder() {
+		reader, err := replica.Adapter.GetObject(ctx, key)
+		if err == nil {
+			return reader, nil
+		}
+		a.markFailure(replica, err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// StatObject returns metadata from the first healthy replica that has the object
+func (a *MirroredStorageAdapter) StatObject(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+	var lastErr error
+	for _, replica := range a.readOrder() {
+		info, err := replica.Adapter.StatObject(ctx, key)
+		if err == nil {
+			return info, nil
+		}
+		a.markFailure(replica, err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// ListObjects lists the primary, which holds every object once Repair has run
+func (a *MirroredStorageAdapter) ListObjects(ctx context.Context, prefix string, fn func(ports.ObjectInfo) error) error {
+	return a.primary().Adapter.ListObjects(ctx, prefix, fn)
+}
+
+// CopyObject copies the object within every replica. Only a primary failure is returned.
+func (a *MirroredStorageAdapter) CopyObject(ctx context.Context, srcKey, dstKey string) error {
+	return a.writeAll(ctx, "copy", dstKey, func(adapter ports.StorageAdapter) error {
+		return adapter.CopyObject(ctx, srcKey, dstKey)
+	})
+}
+
+// Close closes every replica that holds open connections
+func (a *MirroredStorageAdapter) Close() error {
+	var errs []error
+	for _, replica := range a.replicas {
+		if closer, ok := replica.Adapter.(io.Closer); ok {
+			errs = append(errs, closer.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Repair reconciles replicas under prefix: every object on the primary is
+// copied to the secondaries that are missing it or hold a different size.
+// Objects only found on a secondary are left alone: the primary is the source
+// of truth, so they are deletes (e.g. by the GC) a secondary missed, not data
+// the primary lost.
+func (a *MirroredStorageAdapter) Repair(ctx context.Context, prefix string) (*RepairReport, error) {
+	report := &RepairReport{Repaired: make(map[string]int, len(a.replicas))}
+
+	primary := a.primary()
+	sizes := make(map[string]int64)
+	err := primary.Adapter.ListObjects(ctx, prefix, func(obj ports.ObjectInfo) error {
+		sizes[obj.Key] = obj.Size
+		return nil
+	})
+	if err != nil {
+		return report, fmt.Errorf("failed to list replica %s: %w", primary.Name, err)
+	}
+	report.Scanned = len(sizes)
+
+	for _, replica := range a.replicas[1:] {
+		// key -> size on this secondary
+		present := make(map[string]int64)
+		err := replica.Adapter.ListObjects(ctx, prefix, func(obj ports.ObjectInfo) error {
+			present[obj.Key] = obj.Size
+			return nil
+		})
+		if err != nil {
+			log.Printf("[Mirror] Failed to list replica %s: %v", replica.Name, err)
+			report.Failed++
+			continue
+		}
+
+		for key, size := range sizes {
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+			if existing, ok := present[key]; ok && existing == size {
+				continue
+			}
+			if err := a.copyBetween(ctx, key, primary, replica); err != nil {
+				log.Printf("[Mirror] Failed to repair %s on %s: %v", key, replica.Name, err)
+				report.Failed++
+				continue
+			}
+			report.Repaired[replica.Name]++
+		}
+	}
+
+	return report, nil
+}
+
+// copyBetween copies one object from one replica to another
+func (a *MirroredStorageAdapter) copyBetween(ctx context.Context, key string, from, to *Replica) error {
+	info, err := from.Adapter.StatObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	reader, err := from.Adapter.GetObject(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+	return to.Adapter.PutObject(ctx, key, reader, info.ContentType)
+}
+
+// writeAll runs a write on every replica. Secondary failures are only logged;
+// Repair brings those replicas back in line later.
+func (a *MirroredStorageAdapter) writeAll(ctx context.Context, op, key string, write func(ports.StorageAdapter) error) error {
+	var primaryErr error
+	for i, replica := range a.replicas {
+		err := write(replica.Adapter)
+		if err == nil {
+			continue
+		}
+		a.markFailure(replica, err)
+		if i == 0 {
+			primaryErr = err
+			continue
+		}
+		log.Printf("[Mirror] Failed to %s %s on %s: %v", op, key, replica.Name, err)
+	}
+	return primaryErr
+}
+
+func (a *MirroredStorageAdapter) primary() *Replica {
+	return a.replicas[0]
+}
+
+// readOrder returns healthy replicas first, in configured order, followed by
+// the unhealthy ones so a read is still attempted when everything is failing
+func (a *MirroredStorageAdapter) readOrder() []*Replica {
+	now := time.Now().UnixNano()
+	healthy := make([]*Replica, 0, len(a.replicas))
+	var unhealthy []*Replica
+	for _, replica := range a.replicas {
+		if replica.unhealthyUntil.Load() > now {
+			unhealthy = append(unhealthy, replica)
+		} else {
+			healthy = append(healthy, replica)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// markFailure takes a replica out of the read rotation for the cooldown period.
+// A missing object or a cancelled request says nothing about replica health.
+func (a *MirroredStorageAdapter) markFailure(replica *Replica, err error) {
+	if errors.Is(err, ports.ErrObjectNotFound) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return
+	}
+	if replica.unhealthyUntil.Swap(time.Now().Add(a.cooldown).UnixNano()) < time.Now().UnixNano() {
+		log.Printf("[Mirror] Replica %s marked unhealthy for %s: %v", replica.Name, a.cooldown, err)
+	}
+}
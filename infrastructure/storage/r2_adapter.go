@@ -1,23 +1,15 @@
 package storage
 
 import (
-	"context"
 	"fmt"
-	"time"
 
 	"gofiber-smart-trash/domain/ports"
-
-	"github.com/aws/aws-sdk-go-v2/aws"
-	"github.com/aws/aws-sdk-go-v2/config"
-	"github.com/aws/aws-sdk-go-v2/credentials"
-	"github.com/aws/aws-sdk-go-v2/service/s3"
 )
 
 // R2StorageAdapter implements StorageAdapter for Cloudflare R2
+// R2 is S3-compatible, so all operations are delegated to S3StorageAdapter
 type R2StorageAdapter struct {
-	client    *s3.Client
-	bucket    string
-	publicURL string
+	*S3StorageAdapter
 }
 
 // NewR2StorageAdapter creates a new R2 storage adapter
@@ -25,66 +17,21 @@ func NewR2StorageAdapter(accountID, accessKeyID, secretAccessKey, bucket, public
 	// Cloudflare R2 endpoint format
 	endpoint := fmt.Sprintf("https://%s.r2.cloudflarestorage.com", accountID)
 
-	// Load AWS SDK config with R2 credentials
-	cfg, err := config.LoadDefaultConfig(context.Background(),
-		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
-			accessKeyID,
-			secretAccessKey,
-			"",
-		)),
-		config.WithRegion("auto"), // R2 uses "auto" region
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to load AWS config: %w", err)
-	}
-
-	// Create S3 client with R2 endpoint
-	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
-		o.BaseEndpoint = aws.String(endpoint)
+	adapter, err := NewS3StorageAdapter(S3Config{
+		Region:          "auto", // R2 uses "auto" region
+		Endpoint:        endpoint,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		Bucket:          bucket,
+		PublicURL:       publicURL,
 	})
-
-	return &R2StorageAdapter{
-		client:    client,
-		bucket:    bucket,
-		publicURL: publicURL,
-	}, nil
-}
-
-// GeneratePresignedUploadURL generates a presigned URL for uploading objects to R2
-func (a *R2StorageAdapter) GeneratePresignedUploadURL(ctx context.Context, key string, expiry time.Duration) (*ports.PresignedURLResponse, error) {
-	presignClient := s3.NewPresignClient(a.client)
-
-	// Create a presigned PUT request
-	req, err := presignClient.PresignPutObject(ctx, &s3.PutObjectInput{
-		Bucket:      aws.String(a.bucket),
-		Key:         aws.String(key),
-		ContentType: aws.String("image/jpeg"),
-	}, s3.WithPresignExpires(expiry))
-
 	if err != nil {
-		return nil, fmt.Errorf("failed to presign PutObject: %w", err)
+		return nil, err
 	}
 
-	// Generate public URL for accessing the uploaded file
-	publicURL := a.GeneratePublicURL(key)
-
-	return &ports.PresignedURLResponse{
-		UploadURL: req.URL,
-		PublicURL: publicURL,
-		ExpiresIn: int64(expiry.Seconds()),
-	}, nil
-}
+	// R2 does not implement browser-based POST uploads, so size limits
+	// are enforced by signing the exact Content-Length on a PUT instead
+	adapter.supportsPostPolicy = false
 
-// GeneratePublicURL generates a public URL for accessing an object
-func (a *R2StorageAdapter) GeneratePublicURL(key string) string {
-	return fmt.Sprintf("%s/%s", a.publicURL, key)
-}
-
-// DeleteObject removes an object from R2 storage
-func (a *R2StorageAdapter) DeleteObject(ctx context.Context, key string) error {
-	_, err := a.client.DeleteObject(ctx, &s3.DeleteObjectInput{
-		Bucket: aws.String(a.bucket),
-		Key:    aws.String(key),
-	})
-	return err
+	return &R2StorageAdapter{S3StorageAdapter: adapter}, nil
 }
@@ -0,0 +1,325 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"gofiber-smart-trash/domain/ports"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Config holds the settings for an S3-compatible storage backend
+type S3Config struct {
+	Region          string
+	Endpoint        string // Optional custom endpoint (e.g. http://localhost:9000 for MinIO)
+	UsePathStyle    bool   // Use path-style addressing (required by MinIO)
+	AccessKeyID     string // Leave empty to use the default AWS credential chain
+	SecretAccessKey string
+	Bucket          string
+	PublicURL       string // Optional; derived from endpoint/region when empty
+}
+
+// S3StorageAdapter implements StorageAdapter for AWS S3 and S3-compatible services
+type S3StorageAdapter struct {
+	client             *s3.Client
+	bucket             string
+	publicURL          string
+	supportsPostPolicy bool
+}
+
+// NewS3StorageAdapter creates a new S3 storage adapter
+func NewS3StorageAdapter(cfg S3Config) (*S3StorageAdapter, error) {
+	if cfg.Bucket == "" {
+		return nil, fmt.Errorf("S3 bucket is required")
+	}
+	// "auto" is only meaningful for R2; AWS needs a real region
+	if cfg.Region == "" || (cfg.Region == "auto" && cfg.Endpoint == "") {
+		cfg.Region = "us-east-1"
+	}
+
+	opts := []func(*config.LoadOptions) error{
+		config.WithRegion(cfg.Region),
+	}
+	if cfg.AccessKeyID != "" {
+		opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
+			cfg.AccessKeyID,
+			cfg.SecretAccessKey,
+			"",
+		)))
+	}
+
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	client := s3.NewFromConfig(awsCfg, func(o *s3.Options) {
+		if cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(cfg.Endpoint)
+		}
+		o.UsePathStyle = cfg.UsePathStyle
+	})
+
+	publicURL := strings.TrimSuffix(cfg.PublicURL, "/")
+	if publicURL == "" {
+		publicURL = defaultS3PublicURL(cfg)
+	}
+
+	return &S3StorageAdapter{
+		client:             client,
+		bucket:             cfg.Bucket,
+		publicURL:          publicURL,
+		supportsPostPolicy: true,
+	}, nil
+}
+
+// defaultS3PublicURL derives the object base URL when no public URL is configured
+func defaultS3PublicURL(cfg S3Config) string {
+	if cfg.Endpoint != "" {
+		endpoint := strings.TrimSuffix(cfg.Endpoint, "/")
+		if cfg.UsePathStyle {
+			return fmt.Sprintf("%s/%s", endpoint, cfg.Bucket)
+		}
+		scheme, host, found := strings.Cut(endpoint, "://")
+		if !found {
+			return fmt.Sprintf("%s.%s", cfg.Bucket, endpoint)
+		}
+		return fmt.Sprintf("%s://%s.%s", scheme, cfg.Bucket, host)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.Bucket, cfg.Region)
+}
+
+// GeneratePresignedUploadURL generates a presigned URL for uploading objects to S3.
+// A POST policy is used when the size is unknown so the limit is enforced by S3;
+// otherwise a PUT with the exact Content-Length signed is returned.
+func (a *S3StorageAdapter) GeneratePresignedUploadURL(ctx context.Context, key string, opts ports.UploadOptions) (*ports.PresignedURLResponse, error) {
+	if opts.MaxBytes > 0 && opts.ContentLength == 0 && a.supportsPostPolicy {
+		return a.presignPost(ctx, key, opts)
+	}
+	return a.presignPut(ctx, key, opts)
+}
+
+// presignPut creates a presigned PUT request with the constraints signed as headers
+func (a *S3StorageAdapter) presignPut(ctx context.Context, key string, opts ports.UploadOptions) (*ports.PresignedURLResponse, error) {
+	if opts.MaxBytes > 0 {
+		if opts.ContentLength == 0 {
+			return nil, ports.ErrContentLengthRequired
+		}
+		if opts.ContentLength > opts.MaxBytes {
+			return nil, fmt.Errorf("content length %d exceeds limit of %d bytes", opts.ContentLength, opts.MaxBytes)
+		}
+	}
+
+	input := &s3.PutObjectInput{
+		Bucket:      aws.String(a.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(opts.ContentType),
+	}
+	if opts.ContentLength > 0 {
+		input.ContentLength = aws.Int64(opts.ContentLength)
+	}
+	if opts.ContentMD5 != "" {
+		input.ContentMD5 = aws.String(opts.ContentMD5)
+	}
+	if opts.ChecksumSHA256 != "" {
+		input.ChecksumSHA256 = aws.String(opts.ChecksumSHA256)
+	}
+
+	presignClient := s3.NewPresignClient(a.client)
+	req, err := presignClient.PresignPutObject(ctx, input, s3.WithPresignExpires(opts.Expiry))
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign PutObject: %w", err)
+	}
+
+	// The client must replay every signed header exactly
+	headers := make(map[string]string)
+	for name, values := range req.SignedHeader {
+		if strings.EqualFold(name, "Host") || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+
+	return &ports.PresignedURLResponse{
+		UploadURL: req.URL,
+		Method:    http.MethodPut,
+		Headers:   headers,
+		PublicURL: a.GeneratePublicURL(key),
+		ExpiresIn: int64(opts.Expiry.Seconds()),
+	}, nil
+}
+
+// presignPost creates a presigned POST policy limiting content type and size.
+// Checksums cannot be bound by the policy, so they need a PUT with content_length.
+func (a *S3StorageAdapter) presignPost(ctx context.Context, key string, opts ports.UploadOptions) (*ports.PresignedURLResponse, error) {
+	if opts.ContentMD5 != "" || opts.ChecksumSHA256 != "" {
+		return nil, fmt.Errorf("%w: send content_length with a checksum to get a signed PUT URL", ports.ErrUnsupportedChecksum)
+	}
+
+	conditions := []interface{}{
+		[]interface{}{"content-length-range", 1, opts.MaxBytes},
+		map[string]string{"Content-Type": opts.ContentType},
+	}
+
+	presignClient := s3.NewPresignClient(a.client)
+	req, err := presignClient.PresignPostObject(ctx, &s3.PutObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(key),
+	}, func(o *s3.PresignPostOptions) {
+		o.Expires = opts.Expiry
+		o.Conditions = conditions
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign PostObject: %w", err)
+	}
+
+	fields := req.Values
+	fields["Content-Type"] = opts.ContentType
+
+	return &ports.PresignedURLResponse{
+		UploadURL:  req.URL,
+		Method:     http.MethodPost,
+		FormFields: fields,
+		PublicURL:  a.GeneratePublicURL(key),
+		ExpiresIn:  int64(opts.Expiry.Seconds()),
+	}, nil
+}
+
+// copySource builds the x-amz-copy-source value, escaping each key segment but not the slashes between them
+func copySource(bucket, key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return bucket + "/" + strings.Join(segments, "/")
+}
+
+// GeneratePublicURL generates a public URL for accessing an object
+func (a *S3StorageAdapter) GeneratePublicURL(key string) string {
+	return fmt.Sprintf("%s/%s", a.publicURL, key)
+}
+
+// GeneratePresignedDownloadURL generates a presigned GET URL for reading a private object
+func (a *S3StorageAdapter) GeneratePresignedDownloadURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	presignClient := s3.NewPresignClient(a.client)
+	req, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(key),
+	}, s3.WithPresignExpires(expiry))
+	if err != nil {
+		return "", fmt.Errorf("failed to presign GetObject: %w", err)
+	}
+	return req.URL, nil
+}
+
+// DeleteObject removes an object from S3 storage
+func (a *S3StorageAdapter) DeleteObject(ctx context.Context, key string) error {
+	_, err := a.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+// PutObject uploads an object to S3 from the server side
+func (a *S3StorageAdapter) PutObject(ctx context.Context, key string, body io.Reader, contentType string) error {
+	_, err := a.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(a.bucket),
+		Key:         aws.String(key),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put object: %w", err)
+	}
+	return nil
+}
+
+// GetObject opens an object in S3 for reading
+func (a *S3StorageAdapter) GetObject(ctx context.Context, key string) (io.ReadCloser, error) {
+	out, err := a.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound {
+			return nil, ports.ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get object: %w", err)
+	}
+	return out.Body, nil
+}
+
+// StatObject returns metadata for an object in S3
+func (a *S3StorageAdapter) StatObject(ctx context.Context, key string) (*ports.ObjectInfo, error) {
+	out, err := a.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(a.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotFound {
+			return nil, ports.ErrObjectNotFound
+		}
+		return nil, fmt.Errorf("failed to head object: %w", err)
+	}
+
+	return &ports.ObjectInfo{
+		Key:          key,
+		Size:         aws.ToInt64(out.ContentLength),
+		ContentType:  aws.ToString(out.ContentType),
+		ETag:         strings.Trim(aws.ToString(out.ETag), `"`),
+		LastModified: aws.ToTime(out.LastModified),
+	}, nil
+}
+
+// ListObjects walks all objects under a prefix in S3
+func (a *S3StorageAdapter) ListObjects(ctx context.Context, prefix string, fn func(ports.ObjectInfo) error) error {
+	paginator := s3.NewListObjectsV2Paginator(a.client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(a.bucket),
+		Prefix: aws.String(prefix),
+	})
+
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to list objects: %w", err)
+		}
+		for _, obj := range page.Contents {
+			if err := fn(ports.ObjectInfo{
+				Key:          aws.ToString(obj.Key),
+				Size:         aws.ToInt64(obj.Size),
+				ETag:         strings.Trim(aws.ToString(obj.ETag), `"`),
+				LastModified: aws.ToTime(obj.LastModified),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CopyObject copies an object within the S3 bucket
+func (a *S3StorageAdapter) CopyObject(ctx context.Context, srcKey, dstKey string) error {
+	_, err := a.client.CopyObject(ctx, &s3.CopyObjectInput{
+		Bucket:     aws.String(a.bucket),
+		CopySource: aws.String(copySource(a.bucket, srcKey)),
+		Key:        aws.String(dstKey),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy object: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/services"
+	"gofiber-smart-trash/pkg/utils"
+)
+
+// ListBinRuleSets handles GET /api/admin/bin-rules
+// Lists every version of the bin rules, newest first
+func (h *Handlers) ListBinRuleSets(c *fiber.Ctx) error {
+	ruleSets, err := h.binMapping.ListRuleSets(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.APIResponse{
+		Success: true,
+		Data:    ruleSets,
+	})
+}
+
+// GetActiveBinRuleSet handles GET /api/admin/bin-rules/active
+// Returns the rules currently used to choose bins
+func (h *Handlers) GetActiveBinRuleSet(c *fiber.Ctx) error {
+	ruleSet, err := h.binMapping.ActiveRuleSet(c.Context())
+	return binRuleSetResponse(c, ruleSet, err, "No bin rules are active; the AI service's bins are used")
+}
+
+// GetBinRuleSet handles GET /api/admin/bin-rules/:version
+// Returns one version of the bin rules
+func (h *Handlers) GetBinRuleSet(c *fiber.Ctx) error {
+	version, err := c.ParamsInt("version")
+	if err != nil || version < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_VERSION",
+			Message: "Version must be a positive integer",
+		})
+	}
+
+	ruleSet, err := h.binMapping.GetRuleSet(c.Context(), version)
+	return binRuleSetResponse(c, ruleSet, err, "Bin rule version not found")
+}
+
+// CreateBinRuleSet handles POST /api/admin/bin-rules
+// Saves the rules as a new version, active right away unless activate is false
+func (h *Handlers) CreateBinRuleSet(c *fiber.Ctx) error {
+	var req dto.CreateBinRuleSetRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "VALIDATION_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	ruleSet, err := h.binMapping.CreateRuleSet(c.Context(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.APIResponse{
+		Success: true,
+		Message: "Bin rules saved",
+		Data:    ruleSet,
+	})
+}
+
+// ActivateBinRuleSet handles POST /api/admin/bin-rules/:version/activate
+// Makes an earlier or inactive version the one in use, e.g. to roll back
+func (h *Handlers) ActivateBinRuleSet(c *fiber.Ctx) error {
+	version, err := c.ParamsInt("version")
+	if err != nil || version < 1 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_VERSION",
+			Message: "Version must be a positive integer",
+		})
+	}
+
+	ruleSet, err := h.binMapping.ActivateRuleSet(c.Context(), version)
+	return binRuleSetResponse(c, ruleSet, err, "Bin rule version not found")
+}
+
+// binRuleSetResponse writes a rule set or the error of looking it up
+func binRuleSetResponse(c *fiber.Ctx, ruleSet *dto.BinRuleSetResponse, err error, notFound string) error {
+	if errors.Is(err, services.ErrBinRuleSetNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "NOT_FOUND",
+			Message: notFound,
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.APIResponse{
+		Success: true,
+		Data:    ruleSet,
+	})
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/pkg/utils"
+)
+
+// ExportDataset handles GET /api/admin/dataset/export
+// Streams a zip archive of labelled images for retraining the classifier and detector
+func (h *Handlers) ExportDataset(c *fiber.Ctx) error {
+	var req dto.DatasetExportRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "VALIDATION_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	opts, err := datasetExportOptions(&req)
+	if err == nil {
+		err = h.datasetExport.Validate(&opts)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_EXPORT",
+			Message: err.Error(),
+		})
+	}
+
+	fileName := fmt.Sprintf("dataset-%s.zip", time.Now().UTC().Format("20060102-150405"))
+	c.Set(fiber.HeaderContentType, "application/zip")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, fileName))
+
+	// The archive is written after the handler returns. The fasthttp request
+	// context stays valid until the stream ends and is cancelled when the server
+	// shuts down; a client that disconnects fails the next write.
+	ctx := c.Context()
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		report, err := h.datasetExport.Export(ctx, opts, w)
+		if err != nil {
+			log.Printf("[Dataset] Export failed: %v", err)
+			return
+		}
+		w.Flush()
+		log.Printf("[Dataset] Exported %d records (%d failed) as %s", report.Exported, report.Failed, fileName)
+	})
+	return nil
+}
+
+// datasetExportOptions converts the query parameters of an export request
+func datasetExportOptions(req *dto.DatasetExportRequest) (dto.DatasetExportOptions, error) {
+	opts := dto.DatasetExportOptions{
+		MinConfidence:  req.MinConfidence,
+		ReviewStatuses: utils.SplitList(req.ReviewStatus),
+		Formats:        utils.SplitList(req.Formats),
+		TrainRatio:     req.TrainRatio,
+		ValRatio:       req.ValRatio,
+		Seed:           req.Seed,
+	}
+	var err error
+	if opts.CreatedFrom, err = utils.ParseDate(req.From); err != nil {
+		return opts, fmt.Errorf("invalid from: %w", err)
+	}
+	if opts.CreatedTo, err = utils.ParseDate(req.To); err != nil {
+		return opts, fmt.Errorf("invalid to: %w", err)
+	}
+	return opts, nil
+}
@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"net/url"
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-smart-trash/domain/dto"
+)
+
+// UploadFile handles PUT /files/upload/*
+// Receives an image upload for a URL signed by the local storage adapter
+func (h *Handlers) UploadFile(c *fiber.Ctx) error {
+	if h.fileStore == nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "NOT_FOUND",
+			Message: "Local file storage is not enabled",
+		})
+	}
+
+	key := c.Params("*")
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+	}
+
+	if len(c.Body()) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "EMPTY_BODY",
+			Message: "Request body is empty",
+		})
+	}
+
+	// Verify the signed URL and the upload constraints it carries
+	if err := h.fileStore.VerifyUpload(key, query, c.Get(fiber.HeaderContentType), c.Body()); err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "UPLOAD_REJECTED",
+			Message: err.Error(),
+		})
+	}
+
+	// Store the uploaded file
+	if err := h.fileStore.PutObject(c.Context(), key, bytes.NewReader(c.Body()), c.Get(fiber.HeaderContentType)); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// GetFile handles GET /files/*
+// Serves an image stored by the local storage adapter (signed URL required in private mode)
+func (h *Handlers) GetFile(c *fiber.Ctx) error {
+	if h.fileStore == nil {
+		return c.Status(fiber.StatusNotFound).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "NOT_FOUND",
+			Message: "Local file storage is not enabled",
+		})
+	}
+
+	key := c.Params("*")
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err == nil {
+		err = h.fileStore.VerifyDownload(key, query)
+	}
+	if err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "ACCESS_DENIED",
+			Message: err.Error(),
+		})
+	}
+
+	path, err := h.fileStore.ObjectPath(key)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_KEY",
+			Message: err.Error(),
+		})
+	}
+
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "NOT_FOUND",
+			Message: "File not found",
+		})
+	}
+
+	return c.SendFile(path)
+}
@@ -1,17 +1,28 @@
 package handlers
 
 import (
+	"gofiber-smart-trash/domain/ports"
 	"gofiber-smart-trash/domain/services"
 )
 
 // Handlers contains all HTTP handlers and services
 type Handlers struct {
-	trashService services.TrashService
+	trashService  services.TrashService
+	fileStore     ports.LocalFileStore  // nil unless STORAGE_PROVIDER=local
+	aiCircuit     ports.CircuitReporter // nil if the AI adapter has no circuit breaker
+	datasetExport services.DatasetExportService
+	shadowService services.ShadowService
+	binMapping    services.BinMappingService
 }
 
 // NewHandlers creates a new instance of Handlers with all dependencies
-func NewHandlers(trashService services.TrashService) *Handlers {
+func NewHandlers(trashService services.TrashService, fileStore ports.LocalFileStore, aiCircuit ports.CircuitReporter, datasetExport services.DatasetExportService, shadowService services.ShadowService, binMapping services.BinMappingService) *Handlers {
 	return &Handlers{
-		trashService: trashService,
+		trashService:  trashService,
+		fileStore:     fileStore,
+		aiCircuit:     aiCircuit,
+		datasetExport: datasetExport,
+		shadowService: shadowService,
+		binMapping:    binMapping,
 	}
-}
\ No newline at end of file
+}
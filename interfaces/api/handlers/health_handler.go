@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// Health handles GET /health
+// Reports "degraded" while the AI circuit breaker is failing fast; the API itself still accepts uploads
+func (h *Handlers) Health(c *fiber.Ctx) error {
+	response := fiber.Map{
+		"status": "ok",
+	}
+	if h.aiCircuit != nil {
+		circuit := h.aiCircuit.CircuitState()
+		if circuit.State != "closed" {
+			response["status"] = "degraded"
+		}
+		response["ai_circuit"] = circuit
+	}
+	return c.JSON(response)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/services"
+	"gofiber-smart-trash/pkg/utils"
+)
+
+// ListReviews handles GET /api/reviews
+// Lists records whose AI result is waiting for an operator, oldest first
+func (h *Handlers) ListReviews(c *fiber.Ctx) error {
+	var req dto.ListReviewsRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "VALIDATION_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	response, err := h.trashService.ListPendingReviews(c.Context(), &req)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// SubmitReview handles POST /api/trash/:id/review
+// Stores an operator's corrected category and bin for a record
+func (h *Handlers) SubmitReview(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_ID",
+			Message: "Invalid UUID format",
+		})
+	}
+
+	var req dto.SubmitReviewRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+	}
+
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "VALIDATION_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	response, err := h.trashService.SubmitReview(c.Context(), id, &req)
+	if errors.Is(err, services.ErrTrashNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "NOT_FOUND",
+			Message: "Trash record not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
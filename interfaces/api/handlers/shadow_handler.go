@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/services"
+)
+
+// ShadowReport handles GET /api/admin/shadow/report
+// Compares the shadow model's results with production, per category
+func (h *Handlers) ShadowReport(c *fiber.Ctx) error {
+	var req dto.ShadowReportRequest
+
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+	}
+
+	report, err := h.shadowService.AgreementReport(c.Context(), &req)
+	if errors.Is(err, services.ErrInvalidDateRange) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_DATE",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.APIResponse{
+		Success: true,
+		Data:    report,
+	})
+}
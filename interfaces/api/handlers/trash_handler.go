@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
 	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/ports"
+	"gofiber-smart-trash/domain/services"
 	"gofiber-smart-trash/pkg/utils"
 )
 
@@ -33,6 +37,20 @@ func (h *Handlers) CreateTrash(c *fiber.Ctx) error {
 
 	// Create trash record
 	response, err := h.trashService.CreateTrashRecord(c.Context(), &req)
+	if errors.Is(err, services.ErrUploadAlreadyUsed) {
+		return c.Status(fiber.StatusConflict).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "UPLOAD_ALREADY_USED",
+			Message: err.Error(),
+		})
+	}
+	if errors.Is(err, services.ErrInvalidImage) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_IMAGE",
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
 			Success: false,
@@ -41,7 +59,71 @@ func (h *Handlers) CreateTrash(c *fiber.Ctx) error {
 		})
 	}
 
-	return c.Status(fiber.StatusCreated).JSON(dto.APIResponse{
+	return c.Status(createdStatus(response)).JSON(dto.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
+
+// UploadTrash handles POST /api/trash/upload
+// Accepts a multipart image upload for devices that cannot PUT to presigned URLs
+func (h *Handlers) UploadTrash(c *fiber.Ctx) error {
+	var req dto.UploadTrashRequest
+
+	// Parse form fields
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+	}
+
+	// Validate request
+	if err := utils.ValidateStruct(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "VALIDATION_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "MISSING_IMAGE",
+			Message: "image file is required",
+		})
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+	}
+	defer file.Close()
+
+	// Store, classify and create trash record
+	response, err := h.trashService.UploadTrashRecord(c.Context(), &req, file, fileHeader.Size)
+	if errors.Is(err, services.ErrInvalidUpload) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_UPLOAD",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	return c.Status(createdStatus(response)).JSON(dto.APIResponse{
 		Success: true,
 		Data:    response,
 	})
@@ -105,3 +187,59 @@ func (h *Handlers) ListTrash(c *fiber.Ctx) error {
 		Data:    response,
 	})
 }
+
+// createdStatus returns 200 when an existing record was returned for a duplicate submission
+func createdStatus(response *dto.TrashResponse) int {
+	if response.Duplicate {
+		return fiber.StatusOK
+	}
+	return fiber.StatusCreated
+}
+
+// ReclassifyTrash handles POST /api/trash/:id/reclassify
+// Runs AI classification on an existing record again; on failure the previous result is kept
+func (h *Handlers) ReclassifyTrash(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_ID",
+			Message: "Invalid UUID format",
+		})
+	}
+
+	response, err := h.trashService.ReclassifyTrashRecord(c.Context(), id)
+	if errors.Is(err, services.ErrTrashNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "NOT_FOUND",
+			Message: "Trash record not found",
+		})
+	}
+	if errors.Is(err, ports.ErrAIUnavailable) {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "AI_UNAVAILABLE",
+			Message: err.Error(),
+		})
+	}
+	if errors.Is(err, services.ErrClassificationFailed) {
+		return c.Status(fiber.StatusBadGateway).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "CLASSIFICATION_FAILED",
+			Message: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INTERNAL_ERROR",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(dto.APIResponse{
+		Success: true,
+		Data:    response,
+	})
+}
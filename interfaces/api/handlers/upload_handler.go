@@ -1,17 +1,29 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 
 	"gofiber-smart-trash/domain/dto"
+	"gofiber-smart-trash/domain/services"
 )
 
 // GenerateUploadURL handles GET /api/upload-url
 // Returns a presigned URL for uploading trash images to cloud storage
 func (h *Handlers) GenerateUploadURL(c *fiber.Ctx) error {
-	// Get device_id from query parameter
-	deviceID := c.Query("device_id")
-	if deviceID == "" {
+	var req dto.UploadURLRequest
+
+	// Parse query parameters
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_REQUEST",
+			Message: err.Error(),
+		})
+	}
+
+	if req.DeviceID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
 			Success: false,
 			Error:   "MISSING_DEVICE_ID",
@@ -20,7 +32,14 @@ func (h *Handlers) GenerateUploadURL(c *fiber.Ctx) error {
 	}
 
 	// Generate presigned upload URL
-	response, err := h.trashService.GenerateUploadURL(c.Context(), deviceID)
+	response, err := h.trashService.GenerateUploadURL(c.Context(), &req)
+	if errors.Is(err, services.ErrInvalidUpload) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.APIResponse{
+			Success: false,
+			Error:   "INVALID_UPLOAD",
+			Message: err.Error(),
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.APIResponse{
 			Success: false,
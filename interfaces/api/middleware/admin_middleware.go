@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"gofiber-smart-trash/domain/dto"
+)
+
+// AdminAuth only lets through requests with "Authorization: Bearer <apiKey>".
+// With an empty apiKey every request is refused, so the admin API stays off
+// until a key is configured.
+func AdminAuth(apiKey string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if apiKey == "" {
+			return c.Status(fiber.StatusForbidden).JSON(dto.APIResponse{
+				Success: false,
+				Error:   "ADMIN_DISABLED",
+				Message: "Admin API is disabled; set ADMIN_API_KEY to enable it",
+			})
+		}
+
+		token, ok := strings.CutPrefix(c.Get(fiber.HeaderAuthorization), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(apiKey)) != 1 {
+			return c.Status(fiber.StatusUnauthorized).JSON(dto.APIResponse{
+				Success: false,
+				Error:   "UNAUTHORIZED",
+				Message: "Missing or invalid admin API key",
+			})
+		}
+		return c.Next()
+	}
+}
@@ -8,7 +8,7 @@ import (
 )
 
 // SetupRoutes configures all application routes
-func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
+func SetupRoutes(app *fiber.App, h *handlers.Handlers, adminAPIKey string) {
 	// Global middleware
 	app.Use(middleware.Logger())
 	app.Use(middleware.CORS())
@@ -22,11 +22,15 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
 	})
 
 	// Health check endpoint
-	app.Get("/health", func(c *fiber.Ctx) error {
-		return c.JSON(fiber.Map{
-			"status": "ok",
-		})
-	})
+	app.Get("/health", h.Health)
+
+	// Local file storage (only active when STORAGE_PROVIDER=local)
+	files := app.Group("/files")
+	files.Put("/upload/*", h.UploadFile)
+	files.Get("/*", h.GetFile)
+
+	// Operator and ML tooling requires ADMIN_API_KEY
+	adminAuth := middleware.AdminAuth(adminAPIKey)
 
 	// API routes
 	api := app.Group("/api")
@@ -36,6 +40,26 @@ func SetupRoutes(app *fiber.App, h *handlers.Handlers) {
 
 	// Trash management routes
 	api.Post("/trash", h.CreateTrash)
+	api.Post("/trash/upload", h.UploadTrash)
 	api.Get("/trash", h.ListTrash)
 	api.Get("/trash/:id", h.GetTrash)
+	api.Post("/trash/:id/reclassify", adminAuth, h.ReclassifyTrash)
+	api.Post("/trash/:id/review", adminAuth, h.SubmitReview)
+
+	// Human review queue for uncertain classifications
+	api.Get("/reviews", adminAuth, h.ListReviews)
+
+	// Admin API, requires ADMIN_API_KEY
+	admin := api.Group("/admin", adminAuth)
+	admin.Get("/bin-rules", h.ListBinRuleSets)
+	admin.Post("/bin-rules", h.CreateBinRuleSet)
+	admin.Get("/bin-rules/active", h.GetActiveBinRuleSet)
+	admin.Get("/bin-rules/:version", h.GetBinRuleSet)
+	admin.Post("/bin-rules/:version/activate", h.ActivateBinRuleSet)
+
+	// Training dataset export
+	admin.Get("/dataset/export", h.ExportDataset)
+
+	// Shadow model agreement with production
+	admin.Get("/shadow/report", h.ShadowReport)
 }
\ No newline at end of file
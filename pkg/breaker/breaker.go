@@ -0,0 +1,127 @@
+package breaker
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned by Allow while the breaker is rejecting calls
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the position of a circuit breaker
+type State string
+
+const (
+	Closed   State = "closed"    // Calls pass through
+	Open     State = "open"      // Calls fail fast until the open timeout elapses
+	HalfOpen State = "half_open" // One trial call decides whether to close or re-open
+)
+
+// Snapshot is a point-in-time view of a breaker for health reporting
+type Snapshot struct {
+	State               State
+	ConsecutiveFailures int
+	OpenedAt            *time.Time
+}
+
+// Breaker is a consecutive-failure circuit breaker
+type Breaker struct {
+	name             string
+	failureThreshold int
+	openTimeout      time.Duration
+
+	mu            sync.Mutex
+	state         State
+	failures      int
+	openedAt      time.Time
+	trialInFlight bool
+}
+
+// New creates a breaker that opens after failureThreshold consecutive failures
+// and lets a trial call through once openTimeout has elapsed
+func New(name string, failureThreshold int, openTimeout time.Duration) *Breaker {
+	return &Breaker{
+		name:             name,
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		state:            Closed,
+	}
+}
+
+// Allow reports whether a call may proceed. Every allowed call must be
+// followed by Success, Failure or Release.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == Open {
+		if time.Since(b.openedAt) < b.openTimeout {
+			return ErrOpen
+		}
+		b.setState(HalfOpen)
+	}
+	if b.state == HalfOpen {
+		if b.trialInFlight {
+			return ErrOpen
+		}
+		b.trialInFlight = true
+	}
+	return nil
+}
+
+// Success records a call that reached a healthy service
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trialInFlight = false
+	if b.state != Closed {
+		b.setState(Closed)
+	}
+}
+
+// Failure records a call that failed because the service is unhealthy
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trialInFlight = false
+	if b.state == HalfOpen || (b.state == Closed && b.failures >= b.failureThreshold) {
+		b.openedAt = time.Now()
+		b.setState(Open)
+	}
+}
+
+// Release ends an allowed call that says nothing about service health,
+// e.g. one cancelled by the caller
+func (b *Breaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.trialInFlight = false
+}
+
+// Snapshot returns the current state of the breaker
+func (b *Breaker) Snapshot() Snapshot {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	snapshot := Snapshot{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+	}
+	if b.state != Closed {
+		openedAt := b.openedAt
+		snapshot.OpenedAt = &openedAt
+	}
+	return snapshot
+}
+
+func (b *Breaker) setState(state State) {
+	log.Printf("[Breaker] %s: %s -> %s", b.name, b.state, state)
+	b.state = state
+}
@@ -3,20 +3,103 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
+
+	"gofiber-smart-trash/pkg/utils"
 )
 
 type Config struct {
-	App     AppConfig
-	DB      DatabaseConfig
-	Storage StorageConfig
-	AI      AIConfig
+	App           AppConfig
+	DB            DatabaseConfig
+	Storage       StorageConfig
+	StorageMirror StorageMirrorConfig
+	StorageGC     StorageGCConfig
+	AI            AIConfig
+	Review        ReviewConfig
+	BinRules      BinRulesConfig
+	Admin         AdminConfig
+}
+
+// BinRulesConfig controls the bin mapping rules kept in the database
+type BinRulesConfig struct {
+	RefreshInterval time.Duration // How often the active rules are re-read, to pick up changes made through another instance
+}
+
+// AdminConfig protects the /api/admin routes and the review and reclassify endpoints
+type AdminConfig struct {
+	APIKey string // Empty disables the admin API
+}
+
+// ReviewConfig decides which AI results are queued for an operator to check
+type ReviewConfig struct {
+	ConfidenceThreshold float64 // Results below this confidence are reviewed, 0 disables
+	NoObject            bool    // Review results where L0 detected no object
+}
+
+// StorageMirrorConfig lists the secondary providers every image is replicated to
+type StorageMirrorConfig struct {
+	// Secondary providers named in STORAGE_MIRRORS, each read from MIRROR_<NAME>_* vars
+	Names    []string
+	Replicas []StorageConfig
+
+	RepairInterval    time.Duration // How often missing replicas are reconciled, 0 disables
+	UnhealthyCooldown time.Duration // How long a failing replica is skipped for reads
+}
+
+// StorageGCConfig controls the orphaned image garbage collector
+type StorageGCConfig struct {
+	Enabled     bool
+	Interval    time.Duration
+	GracePeriod time.Duration // Never touch images younger than this
+	Mode        string        // delete, quarantine
+	DryRun      bool
 }
 
 type AIConfig struct {
 	ServiceURL string
-	Timeout    int // in seconds
+	Timeout    int    // in seconds
+	Input      string // url, bytes: how images reach the AI service
+
+	// Classification mode: sync classifies inside the request, async queues a job
+	Mode         string // sync, async
+	Workers      int    // Queue workers started by the API in async mode
+	PollInterval time.Duration
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	JobLease     time.Duration
+
+	// Per-call retries and circuit breaker around the classifier HTTP client
+	CallMaxAttempts    int // 1 disables retries
+	CallBaseDelay      time.Duration
+	CallMaxDelay       time.Duration
+	CallMaxElapsed     time.Duration // Cap on all attempts of one call together, so sync requests cannot hang
+	BreakerThreshold   int           // Consecutive failures before the breaker opens, 0 disables it
+	BreakerOpenTimeout time.Duration
+
+	// Classifier backends in order of preference; without AI_BACKENDS,
+	// AI_SERVICE_URL is the only backend, named "default"
+	Backends            []AIBackendConfig
+	LoadBalance         bool          // Rotate over a backend's healthy replicas
+	BackendCooldown     time.Duration // How long a failing replica is skipped
+	HealthCheckInterval time.Duration // How often the API probes every replica
+
+	// Shadow mode: a candidate model also classifies live traffic, results are only compared
+	ShadowURL         string // Empty disables shadow mode
+	ShadowTimeout     int    // in seconds
+	ShadowInput       string // url, bytes
+	ShadowSampleRate  float64
+	ShadowConcurrency int
+}
+
+// AIBackendConfig is one classifier deployment, e.g. the GPU or the CPU model
+type AIBackendConfig struct {
+	Name    string
+	URLs    []string // Replicas serving the same model
+	Timeout int      // in seconds
+	Input   string   // url, bytes
 }
 
 type AppConfig struct {
@@ -35,20 +118,50 @@ type DatabaseConfig struct {
 }
 
 type StorageConfig struct {
-	Provider        string // r2, s3, gcs
+	Provider        string // r2, s3, gcs, local
 	Bucket          string
 	PublicURL       string
 	PresignedExpiry int64 // in seconds
 
+	// Private bucket mode: images are only readable through short-lived signed URLs
+	Private      bool
+	SignedURLTTL time.Duration
+
+	// Upload constraints applied to presigned URLs
+	MaxUploadBytes      int64
+	AllowedContentTypes []string
+
+	// Resized variants (thumbnail and medium) generated for every image
+	VariantsEnabled bool
+	ThumbnailSize   int
+	MediumSize      int
+
+	// EXIF handling: stored images are rewritten without metadata
+	StripMetadata          bool
+	LocationMismatchMeters float64 // EXIF GPS further than this from the submitted coordinates is flagged
+
+	// Duplicate submission detection: exact (SHA-256) and near (perceptual hash) matches
+	DedupEnabled        bool
+	DedupWindow         time.Duration // How far back a device's records are compared
+	DedupPHashThreshold int           // Max differing bits for a near-duplicate, negative (default) for exact matches only
+
 	// Cloudflare R2 / AWS S3 specific
 	AccountID       string
 	AccessKeyID     string
 	SecretAccessKey string
 	Region          string
+	Endpoint        string // Custom S3 endpoint (e.g. MinIO)
+	UsePathStyle    bool   // Path-style addressing for S3-compatible services
 
 	// GCS specific
 	ProjectID       string
 	CredentialsPath string
+	EmulatorHost    string // fake-gcs-server endpoint for local testing
+
+	// Local filesystem specific
+	LocalPath    string
+	LocalBaseURL string // URL devices use to reach this API
+	LocalSecret  string // HMAC secret for signed upload URLs
 }
 
 func LoadConfig() (*Config, error) {
@@ -59,8 +172,21 @@ func LoadConfig() (*Config, error) {
 		// return nil, err
 	}
 
-	presignedExpiry, _ := strconv.ParseInt(getEnv("PRESIGNED_URL_EXPIRY", "900"), 10, 64)
+	aiServiceURL := getEnv("AI_SERVICE_URL", "http://localhost:8081")
 	aiTimeout, _ := strconv.Atoi(getEnv("AI_TIMEOUT", "30"))
+	aiInput := getEnv("AI_INPUT", "url")
+	aiWorkers, _ := strconv.Atoi(getEnv("CLASSIFICATION_WORKERS", "2"))
+	aiMaxAttempts, _ := strconv.Atoi(getEnv("CLASSIFICATION_MAX_ATTEMPTS", "3"))
+	aiCallMaxAttempts, _ := strconv.Atoi(getEnv("AI_RETRY_MAX_ATTEMPTS", "3"))
+	aiBreakerThreshold, _ := strconv.Atoi(getEnv("AI_BREAKER_FAILURE_THRESHOLD", "5"))
+	aiLoadBalance, _ := strconv.ParseBool(getEnv("AI_LOAD_BALANCE", "false"))
+	aiShadowTimeout, _ := strconv.Atoi(getEnv("AI_SHADOW_TIMEOUT", getEnv("AI_TIMEOUT", "30")))
+	aiShadowSampleRate, _ := strconv.ParseFloat(getEnv("AI_SHADOW_SAMPLE_RATE", "1"), 64)
+	aiShadowConcurrency, _ := strconv.Atoi(getEnv("AI_SHADOW_CONCURRENCY", "2"))
+	reviewThreshold, _ := strconv.ParseFloat(getEnv("REVIEW_CONFIDENCE_THRESHOLD", "0.6"), 64)
+	reviewNoObject, _ := strconv.ParseBool(getEnv("REVIEW_NO_OBJECT", "true"))
+	gcEnabled, _ := strconv.ParseBool(getEnv("STORAGE_GC_ENABLED", "false"))
+	gcDryRun, _ := strconv.ParseBool(getEnv("STORAGE_GC_DRY_RUN", "false"))
 
 	config := &Config{
 		App: AppConfig{
@@ -68,9 +194,53 @@ func LoadConfig() (*Config, error) {
 			Port: getEnv("PORT", "3000"),
 			Env:  getEnv("ENV", "development"),
 		},
+		StorageMirror: loadStorageMirrorConfig(),
+		StorageGC: StorageGCConfig{
+			Enabled:     gcEnabled,
+			Interval:    getDurationEnv("STORAGE_GC_INTERVAL", 6*time.Hour),
+			GracePeriod: getDurationEnv("STORAGE_GC_GRACE_PERIOD", 48*time.Hour),
+			Mode:        getEnv("STORAGE_GC_MODE", "quarantine"),
+			DryRun:      gcDryRun,
+		},
 		AI: AIConfig{
-			ServiceURL: getEnv("AI_SERVICE_URL", "http://localhost:8081"),
+			ServiceURL: aiServiceURL,
 			Timeout:    aiTimeout,
+			Input:      aiInput,
+
+			Mode:         getEnv("CLASSIFICATION_MODE", "sync"),
+			Workers:      aiWorkers,
+			PollInterval: getDurationEnv("CLASSIFICATION_POLL_INTERVAL", 2*time.Second),
+			MaxAttempts:  aiMaxAttempts,
+			RetryBackoff: getDurationEnv("CLASSIFICATION_RETRY_BACKOFF", 30*time.Second),
+			JobLease:     getDurationEnv("CLASSIFICATION_JOB_LEASE", 5*time.Minute),
+
+			CallMaxAttempts:    aiCallMaxAttempts,
+			CallBaseDelay:      getDurationEnv("AI_RETRY_BASE_DELAY", 500*time.Millisecond),
+			CallMaxDelay:       getDurationEnv("AI_RETRY_MAX_DELAY", 5*time.Second),
+			CallMaxElapsed:     getDurationEnv("AI_RETRY_MAX_ELAPSED", 45*time.Second),
+			BreakerThreshold:   aiBreakerThreshold,
+			BreakerOpenTimeout: getDurationEnv("AI_BREAKER_OPEN_TIMEOUT", 30*time.Second),
+
+			Backends:            loadAIBackends(aiServiceURL, aiTimeout, aiInput),
+			LoadBalance:         aiLoadBalance,
+			BackendCooldown:     getDurationEnv("AI_BACKEND_COOLDOWN", 30*time.Second),
+			HealthCheckInterval: getDurationEnv("AI_HEALTH_CHECK_INTERVAL", 30*time.Second),
+
+			ShadowURL:         getEnv("AI_SHADOW_URL", ""),
+			ShadowTimeout:     aiShadowTimeout,
+			ShadowInput:       getEnv("AI_SHADOW_INPUT", aiInput),
+			ShadowSampleRate:  aiShadowSampleRate,
+			ShadowConcurrency: aiShadowConcurrency,
+		},
+		Review: ReviewConfig{
+			ConfidenceThreshold: reviewThreshold,
+			NoObject:            reviewNoObject,
+		},
+		BinRules: BinRulesConfig{
+			RefreshInterval: getDurationEnv("BIN_RULES_REFRESH_INTERVAL", time.Minute),
+		},
+		Admin: AdminConfig{
+			APIKey: getEnv("ADMIN_API_KEY", ""),
 		},
 		DB: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -80,31 +250,143 @@ func LoadConfig() (*Config, error) {
 			DBName:   getEnv("DB_NAME", "smartpicker"),
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
 		},
-		Storage: StorageConfig{
-			Provider:        getEnv("STORAGE_PROVIDER", "r2"),
-			Bucket:          getEnv("R2_BUCKET", "smart-picker-bucket"),
-			PublicURL:       getEnv("R2_PUBLIC_URL", ""),
-			PresignedExpiry: presignedExpiry,
-
-			// R2/S3
-			AccountID:       getEnv("R2_ACCOUNT_ID", ""),
-			AccessKeyID:     getEnv("R2_ACCESS_KEY_ID", ""),
-			SecretAccessKey: getEnv("R2_SECRET_ACCESS_KEY", ""),
-			Region:          getEnv("AWS_REGION", "auto"),
-
-			// GCS (for future use)
-			ProjectID:       getEnv("GCS_PROJECT_ID", ""),
-			CredentialsPath: getEnv("GCS_CREDENTIALS_PATH", ""),
-		},
+		Storage: LoadStorageConfig(""),
 	}
 
 	return config, nil
 }
 
+// LoadStorageConfig reads the storage settings. Provider, bucket, credential and
+// URL settings are read from env vars with the given prefix (e.g. MIGRATE_TARGET_)
+// so a second provider can be configured alongside the main one; upload and image
+// processing settings are always unprefixed.
+func LoadStorageConfig(prefix string) StorageConfig {
+	env := func(key, defaultValue string) string {
+		return getEnv(prefix+key, defaultValue)
+	}
+
+	presignedExpiry, _ := strconv.ParseInt(getEnv("PRESIGNED_URL_EXPIRY", "900"), 10, 64)
+	maxUploadBytes, _ := strconv.ParseInt(getEnv("UPLOAD_MAX_BYTES", "5242880"), 10, 64)
+	usePathStyle, _ := strconv.ParseBool(env("S3_USE_PATH_STYLE", "false"))
+	storagePrivate, _ := strconv.ParseBool(env("STORAGE_PRIVATE", "false"))
+	variantsEnabled, _ := strconv.ParseBool(getEnv("IMAGE_VARIANTS_ENABLED", "true"))
+	thumbnailSize, _ := strconv.Atoi(getEnv("IMAGE_THUMBNAIL_SIZE", "200"))
+	mediumSize, _ := strconv.Atoi(getEnv("IMAGE_MEDIUM_SIZE", "800"))
+	stripMetadata, _ := strconv.ParseBool(getEnv("EXIF_STRIP_ENABLED", "true"))
+	locationMismatchMeters, _ := strconv.ParseFloat(getEnv("EXIF_GPS_MISMATCH_METERS", "500"), 64)
+	dedupEnabled, _ := strconv.ParseBool(getEnv("DEDUP_ENABLED", "true"))
+	dedupThreshold, _ := strconv.Atoi(getEnv("DEDUP_PHASH_THRESHOLD", "-1"))
+
+	return StorageConfig{
+		Provider:        env("STORAGE_PROVIDER", "r2"),
+		Bucket:          env("R2_BUCKET", "smart-picker-bucket"),
+		PublicURL:       env("R2_PUBLIC_URL", ""),
+		PresignedExpiry: presignedExpiry,
+		Private:         storagePrivate,
+		SignedURLTTL:    getDurationEnv("SIGNED_URL_TTL", 15*time.Minute),
+
+		MaxUploadBytes:      maxUploadBytes,
+		AllowedContentTypes: utils.SplitList(getEnv("UPLOAD_ALLOWED_CONTENT_TYPES", "image/jpeg,image/png,image/webp")),
+
+		VariantsEnabled: variantsEnabled,
+		ThumbnailSize:   thumbnailSize,
+		MediumSize:      mediumSize,
+
+		StripMetadata:          stripMetadata,
+		LocationMismatchMeters: locationMismatchMeters,
+
+		DedupEnabled:        dedupEnabled,
+		DedupWindow:         getDurationEnv("DEDUP_WINDOW", 10*time.Minute),
+		DedupPHashThreshold: dedupThreshold,
+
+		// R2/S3
+		AccountID:       env("R2_ACCOUNT_ID", ""),
+		AccessKeyID:     env("R2_ACCESS_KEY_ID", os.Getenv(prefix+"AWS_ACCESS_KEY_ID")),
+		SecretAccessKey: env("R2_SECRET_ACCESS_KEY", os.Getenv(prefix+"AWS_SECRET_ACCESS_KEY")),
+		Region:          env("AWS_REGION", "auto"),
+		Endpoint:        env("S3_ENDPOINT", ""),
+		UsePathStyle:    usePathStyle,
+
+		// GCS
+		ProjectID:       env("GCS_PROJECT_ID", ""),
+		CredentialsPath: env("GCS_CREDENTIALS_PATH", ""),
+		EmulatorHost:    env("GCS_EMULATOR_HOST", ""),
+
+		// Local filesystem
+		LocalPath:    env("LOCAL_STORAGE_PATH", "./uploads"),
+		LocalBaseURL: env("LOCAL_STORAGE_BASE_URL", "http://localhost:"+getEnv("PORT", "3000")),
+		LocalSecret:  env("LOCAL_STORAGE_SECRET", ""),
+	}
+}
+
+// loadStorageMirrorConfig reads STORAGE_MIRRORS=backup,... and the
+// MIRROR_BACKUP_STORAGE_PROVIDER, MIRROR_BACKUP_LOCAL_STORAGE_PATH, ... vars of each mirror
+func loadStorageMirrorConfig() StorageMirrorConfig {
+	cfg := StorageMirrorConfig{
+		RepairInterval:    getDurationEnv("STORAGE_MIRROR_REPAIR_INTERVAL", time.Hour),
+		UnhealthyCooldown: getDurationEnv("STORAGE_MIRROR_UNHEALTHY_COOLDOWN", 30*time.Second),
+	}
+	// getDurationEnv treats 0 as unset, but here it turns the repair job off
+	if interval, err := time.ParseDuration(os.Getenv("STORAGE_MIRROR_REPAIR_INTERVAL")); err == nil && interval == 0 {
+		cfg.RepairInterval = 0
+	}
+	for _, name := range strings.Split(getEnv("STORAGE_MIRRORS", ""), ",") {
+		name = strings.ToUpper(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		replica := LoadStorageConfig("MIRROR_" + name + "_")
+		// No default provider: a mirror must say what it is
+		replica.Provider = getEnv("MIRROR_"+name+"_STORAGE_PROVIDER", "")
+		// Mirrors hold the same images, so they are exactly as private as the primary
+		replica.Private, _ = strconv.ParseBool(getEnv("STORAGE_PRIVATE", "false"))
+		cfg.Names = append(cfg.Names, name)
+		cfg.Replicas = append(cfg.Replicas, replica)
+	}
+	return cfg
+}
+
+// loadAIBackends reads AI_BACKENDS=gpu,cpu and the AI_BACKEND_GPU_URLS=http://gpu-1:8081,http://gpu-2:8081,
+// AI_BACKEND_GPU_TIMEOUT and AI_BACKEND_GPU_INPUT vars of each backend. Timeouts
+// default to AI_TIMEOUT and inputs to AI_INPUT.
+func loadAIBackends(serviceURL string, timeout int, input string) []AIBackendConfig {
+	var backends []AIBackendConfig
+	for _, name := range strings.Split(getEnv("AI_BACKENDS", ""), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		prefix := "AI_BACKEND_" + strings.ToUpper(name) + "_"
+		backend := AIBackendConfig{Name: name, Timeout: timeout, Input: getEnv(prefix+"INPUT", input)}
+		for _, url := range strings.Split(getEnv(prefix+"URLS", ""), ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				backend.URLs = append(backend.URLs, url)
+			}
+		}
+		if backendTimeout, err := strconv.Atoi(getEnv(prefix+"TIMEOUT", "")); err == nil && backendTimeout > 0 {
+			backend.Timeout = backendTimeout
+		}
+		backends = append(backends, backend)
+	}
+	if len(backends) == 0 {
+		backends = append(backends, AIBackendConfig{Name: "default", URLs: []string{serviceURL}, Timeout: timeout, Input: input})
+	}
+	return backends
+}
+
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
 	if value == "" {
 		return defaultValue
 	}
 	return value
-}
\ No newline at end of file
+}
+
+// getDurationEnv parses a duration such as "30m" or "48h", falling back to the default if unset or invalid
+func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || value <= 0 {
+		return defaultValue
+	}
+	return value
+}
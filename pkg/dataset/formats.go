@@ -0,0 +1,86 @@
+package dataset
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Box is an axis-aligned bounding box in pixels from the top-left corner
+type Box struct {
+	X      float64
+	Y      float64
+	Width  float64
+	Height float64
+}
+
+// ClassName turns a label into a lowercase name safe to use as a directory
+func ClassName(label string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(strings.TrimSpace(label)) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ', r == '/', r == '.':
+			b.WriteRune('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "unknown"
+	}
+	return b.String()
+}
+
+// YOLOLine formats one detection as "class cx cy w h" with coordinates
+// normalised to the image size
+func YOLOLine(class int, box Box, imageWidth, imageHeight int) string {
+	w, h := float64(imageWidth), float64(imageHeight)
+	return fmt.Sprintf("%d %.6f %.6f %.6f %.6f", class,
+		(box.X+box.Width/2)/w, (box.Y+box.Height/2)/h, box.Width/w, box.Height/h)
+}
+
+// YOLODataYAML renders the data.yaml of an Ultralytics-style dataset rooted at the directory holding it
+func YOLODataYAML(names []string) string {
+	var b strings.Builder
+	b.WriteString("path: .\ntrain: images/train\nval: images/val\ntest: images/test\n")
+	fmt.Fprintf(&b, "nc: %d\nnames:\n", len(names))
+	for i, name := range names {
+		fmt.Fprintf(&b, "  %d: %s\n", i, name)
+	}
+	return b.String()
+}
+
+// COCOFile is a COCO object detection annotation file
+type COCOFile struct {
+	Info        COCOInfo         `json:"info"`
+	Images      []COCOImage      `json:"images"`
+	Annotations []COCOAnnotation `json:"annotations"`
+	Categories  []COCOCategory   `json:"categories"`
+}
+
+type COCOInfo struct {
+	Description string    `json:"description"`
+	DateCreated time.Time `json:"date_created"`
+}
+
+type COCOImage struct {
+	ID       int    `json:"id"`
+	FileName string `json:"file_name"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+type COCOAnnotation struct {
+	ID         int        `json:"id"`
+	ImageID    int        `json:"image_id"`
+	CategoryID int        `json:"category_id"`
+	BBox       [4]float64 `json:"bbox"` // x, y, width, height in pixels
+	Area       float64    `json:"area"`
+	IsCrowd    int        `json:"iscrowd"`
+	Score      float64    `json:"score,omitempty"` // Model confidence, absent for human labels
+}
+
+type COCOCategory struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
@@ -0,0 +1,46 @@
+package dataset
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+)
+
+// Split names
+const (
+	Train = "train"
+	Val   = "val"
+	Test  = "test"
+)
+
+// Splits lists the split names in output order
+var Splits = []string{Train, Val, Test}
+
+// Ratios are the train and validation fractions; the rest goes to test
+type Ratios struct {
+	Train float64
+	Val   float64
+}
+
+// Validate checks that the ratios are within [0, 1] and leave a non-negative test share
+func (r Ratios) Validate() error {
+	if r.Train < 0 || r.Val < 0 || r.Train+r.Val > 1 {
+		return fmt.Errorf("invalid split ratios: train %.2f, val %.2f", r.Train, r.Val)
+	}
+	return nil
+}
+
+// Assign places key in a split by hashing it with seed. The same key always
+// lands in the same split, so records keep their split as the dataset grows.
+func Assign(key, seed string, ratios Ratios) string {
+	sum := sha256.Sum256([]byte(seed + ":" + key))
+	fraction := float64(binary.BigEndian.Uint64(sum[:8])>>11) / (1 << 53)
+	switch {
+	case fraction < ratios.Train:
+		return Train
+	case fraction < ratios.Train+ratios.Val:
+		return Val
+	default:
+		return Test
+	}
+}
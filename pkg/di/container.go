@@ -1,15 +1,23 @@
 package di
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"log"
+	"strings"
+	"time"
 
 	"gofiber-smart-trash/application/services"
+	"gofiber-smart-trash/domain/dto"
 	"gofiber-smart-trash/domain/ports"
+	"gofiber-smart-trash/domain/repositories"
 	domainServices "gofiber-smart-trash/domain/services"
 	"gofiber-smart-trash/infrastructure/ai"
 	"gofiber-smart-trash/infrastructure/postgres"
 	"gofiber-smart-trash/infrastructure/storage"
 	"gofiber-smart-trash/pkg/config"
+	"gofiber-smart-trash/pkg/jobs"
 
 	"gorm.io/gorm"
 )
@@ -20,12 +28,32 @@ type Container struct {
 	Config *config.Config
 
 	// Infrastructure
-	DB             *gorm.DB
-	StorageAdapter ports.StorageAdapter
-	AIAdapter      ports.AIAdapter
+	DB              *gorm.DB
+	StorageAdapter  ports.StorageAdapter
+	LocalFileStore  ports.LocalFileStore            // Set only when a local storage provider is in use
+	MirroredStorage *storage.MirroredStorageAdapter // Set only when STORAGE_MIRRORS is configured
+	AIAdapter       ports.AIAdapter
+	AIRouter        *ai.Router      // Backends behind AIAdapter, probed by the health check job
+	ShadowAIAdapter ports.AIAdapter // Candidate model in shadow mode, nil when disabled
+
+	// Repositories
+	TrashRepo             repositories.TrashRepository
+	UploadRepo            repositories.UploadSessionRepository
+	ClassificationJobRepo repositories.ClassificationJobRepository
+	ReviewRepo            repositories.ClassificationReviewRepository
+	ShadowRepo            repositories.ShadowClassificationRepository
+	BinRuleRepo           repositories.BinRuleRepository
 
 	// Services
-	TrashService domainServices.TrashService
+	TrashService          domainServices.TrashService
+	ClassificationService domainServices.ClassificationService
+	StorageGCService      domainServices.StorageGCService
+	DatasetExportService  domainServices.DatasetExportService
+	ShadowService         domainServices.ShadowService
+	BinMappingService     domainServices.BinMappingService
+
+	// Background jobs
+	stopJobs context.CancelFunc
 }
 
 func NewContainer() *Container {
@@ -95,75 +123,312 @@ func (c *Container) initDatabase() error {
 }
 
 func (c *Container) initStorageAdapter() error {
-	// Initialize storage adapter based on provider
-	switch c.Config.Storage.Provider {
-	case "r2":
-		adapter, err := storage.NewR2StorageAdapter(
-			c.Config.Storage.AccountID,
-			c.Config.Storage.AccessKeyID,
-			c.Config.Storage.SecretAccessKey,
-			c.Config.Storage.Bucket,
-			c.Config.Storage.PublicURL,
-		)
+	adapter, err := NewStorageAdapter(c.Config.Storage)
+	if err != nil {
+		return err
+	}
+	c.StorageAdapter = adapter
+	if store, ok := adapter.(ports.LocalFileStore); ok {
+		c.LocalFileStore = store
+	}
+
+	// Wrap the primary with its mirrors so every write is replicated
+	if mirrors := c.Config.StorageMirror; len(mirrors.Replicas) > 0 {
+		replicas := []*storage.Replica{{Name: c.Config.Storage.Provider, Adapter: adapter}}
+		for i, cfg := range mirrors.Replicas {
+			secondary, err := NewStorageAdapter(cfg)
+			if err != nil {
+				return fmt.Errorf("failed to initialize storage mirror %s: %w", mirrors.Names[i], err)
+			}
+			replicas = append(replicas, &storage.Replica{Name: mirrors.Names[i], Adapter: secondary})
+			// A local mirror serves signed reads through /files when the primary is down
+			if store, ok := secondary.(ports.LocalFileStore); ok && c.LocalFileStore == nil {
+				c.LocalFileStore = store
+			}
+		}
+
+		mirrored, err := storage.NewMirroredStorageAdapter(replicas, mirrors.UnhealthyCooldown)
 		if err != nil {
 			return err
 		}
-		c.StorageAdapter = adapter
-		log.Println("✓ R2 Storage Adapter initialized")
+		c.StorageAdapter = mirrored
+		c.MirroredStorage = mirrored
+		log.Printf("✓ Storage mirrors enabled (%s)", strings.Join(mirrors.Names, ", "))
+	}
 
-	// Future: Add support for other providers
-	// case "s3":
-	//     adapter, err := storage.NewS3StorageAdapter(...)
-	//     c.StorageAdapter = adapter
-	// case "gcs":
-	//     adapter, err := storage.NewGCSStorageAdapter(...)
-	//     c.StorageAdapter = adapter
+	if c.Config.Storage.Private {
+		log.Printf("✓ Private bucket mode enabled (Signed URL TTL: %s)", c.Config.Storage.SignedURLTTL)
+	}
 
-	default:
-		log.Printf("Warning: Unknown storage provider '%s', using R2 as default", c.Config.Storage.Provider)
+	return nil
+}
+
+// NewStorageAdapter creates the storage adapter for the configured provider
+func NewStorageAdapter(cfg config.StorageConfig) (ports.StorageAdapter, error) {
+	switch cfg.Provider {
+	case "r2":
 		adapter, err := storage.NewR2StorageAdapter(
-			c.Config.Storage.AccountID,
-			c.Config.Storage.AccessKeyID,
-			c.Config.Storage.SecretAccessKey,
-			c.Config.Storage.Bucket,
-			c.Config.Storage.PublicURL,
+			cfg.AccountID,
+			cfg.AccessKeyID,
+			cfg.SecretAccessKey,
+			cfg.Bucket,
+			cfg.PublicURL,
 		)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		c.StorageAdapter = adapter
-		log.Println("✓ R2 Storage Adapter initialized (default)")
-	}
+		log.Println("✓ R2 Storage Adapter initialized")
+		return adapter, nil
+
+	case "s3":
+		adapter, err := storage.NewS3StorageAdapter(storage.S3Config{
+			Region:          cfg.Region,
+			Endpoint:        cfg.Endpoint,
+			UsePathStyle:    cfg.UsePathStyle,
+			AccessKeyID:     cfg.AccessKeyID,
+			SecretAccessKey: cfg.SecretAccessKey,
+			Bucket:          cfg.Bucket,
+			PublicURL:       cfg.PublicURL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("✓ S3 Storage Adapter initialized (Region: %s)", cfg.Region)
+		return adapter, nil
+
+	case "gcs":
+		adapter, err := storage.NewGCSStorageAdapter(storage.GCSConfig{
+			CredentialsPath: cfg.CredentialsPath,
+			Bucket:          cfg.Bucket,
+			PublicURL:       cfg.PublicURL,
+			EmulatorHost:    cfg.EmulatorHost,
+		})
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("✓ GCS Storage Adapter initialized (Bucket: %s)", cfg.Bucket)
+		return adapter, nil
+
+	case "local":
+		adapter, err := storage.NewLocalStorageAdapter(storage.LocalConfig{
+			BasePath: cfg.LocalPath,
+			BaseURL:  cfg.LocalBaseURL,
+			Secret:   cfg.LocalSecret,
+			Private:  cfg.Private,
+		})
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("✓ Local Storage Adapter initialized (Path: %s)", cfg.LocalPath)
+		return adapter, nil
 
-	return nil
+	default:
+		return nil, fmt.Errorf("unknown storage provider '%s'", cfg.Provider)
+	}
 }
 
 func (c *Container) initAIAdapter() error {
-	// Initialize AI adapter for classification service
-	c.AIAdapter = ai.NewClassifierClient(
-		c.Config.AI.ServiceURL,
-		c.Config.AI.Timeout,
+	if c.Config.AI.Mode != "sync" && c.Config.AI.Mode != "async" {
+		return fmt.Errorf("unknown classification mode '%s'", c.Config.AI.Mode)
+	}
+
+	// Route calls over the configured backends, falling back in order of preference
+	var (
+		backends []*ai.Backend
+		names    []string
 	)
+	for _, cfg := range c.Config.AI.Backends {
+		if err := checkAIInput(cfg.Input); err != nil {
+			return fmt.Errorf("AI backend %s: %w", cfg.Name, err)
+		}
+		backend := &ai.Backend{Name: cfg.Name}
+		for _, url := range cfg.URLs {
+			backend.Replicas = append(backend.Replicas, &ai.Replica{
+				URL:     url,
+				Adapter: ai.NewClassifierClient(url, cfg.Timeout, cfg.Input),
+			})
+		}
+		backends = append(backends, backend)
+		names = append(names, fmt.Sprintf("%s x%d (%s)", cfg.Name, len(cfg.URLs), cfg.Input))
+	}
+	router, err := ai.NewRouter(backends, ai.RouterConfig{
+		LoadBalance:       c.Config.AI.LoadBalance,
+		UnhealthyCooldown: c.Config.AI.BackendCooldown,
+	})
+	if err != nil {
+		return err
+	}
+	c.AIRouter = router
+
+	// Retried and guarded by a circuit breaker that opens once every backend is failing
+	c.AIAdapter = ai.NewResilientClient(router, ai.ResilienceConfig{
+		MaxAttempts:        c.Config.AI.CallMaxAttempts,
+		BaseDelay:          c.Config.AI.CallBaseDelay,
+		MaxDelay:           c.Config.AI.CallMaxDelay,
+		MaxElapsed:         c.Config.AI.CallMaxElapsed,
+		BreakerThreshold:   c.Config.AI.BreakerThreshold,
+		BreakerOpenTimeout: c.Config.AI.BreakerOpenTimeout,
+	})
+
+	log.Printf("✓ AI Adapter initialized (Backends: %s, Load balance: %v, Mode: %s, Attempts: %d, Breaker threshold: %d)",
+		strings.Join(names, ", "), c.Config.AI.LoadBalance, c.Config.AI.Mode, c.Config.AI.CallMaxAttempts, c.Config.AI.BreakerThreshold)
+
+	// Candidate model for shadow mode: no retries, its failures are only recorded
+	if c.Config.AI.ShadowURL != "" {
+		if err := checkAIInput(c.Config.AI.ShadowInput); err != nil {
+			return fmt.Errorf("AI shadow: %w", err)
+		}
+		c.ShadowAIAdapter = ai.NewClassifierClient(c.Config.AI.ShadowURL, c.Config.AI.ShadowTimeout, c.Config.AI.ShadowInput)
+		log.Printf("✓ Shadow AI Adapter initialized (URL: %s, Sample rate: %.2f, Concurrency: %d)",
+			c.Config.AI.ShadowURL, c.Config.AI.ShadowSampleRate, c.Config.AI.ShadowConcurrency)
+	}
+	return nil
+}
 
-	log.Printf("✓ AI Adapter initialized (URL: %s, Timeout: %ds)", c.Config.AI.ServiceURL, c.Config.AI.Timeout)
+// checkAIInput validates how a classifier backend receives images
+func checkAIInput(input string) error {
+	if input != ai.InputURL && input != ai.InputBytes {
+		return fmt.Errorf("unknown AI input '%s'", input)
+	}
 	return nil
 }
 
+// aiNeedsImageData reports whether any classifier, including the shadow model,
+// gets image bytes, so images must be read from storage before classifying
+func (c *Container) aiNeedsImageData() bool {
+	for _, backend := range c.Config.AI.Backends {
+		if backend.Input == ai.InputBytes {
+			return true
+		}
+	}
+	return c.Config.AI.ShadowURL != "" && c.Config.AI.ShadowInput == ai.InputBytes
+}
+
 func (c *Container) initServices() error {
-	// Initialize repository
-	trashRepo := postgres.NewTrashRepository(c.DB)
+	// Initialize repositories
+	c.TrashRepo = postgres.NewTrashRepository(c.DB)
+	c.UploadRepo = postgres.NewUploadSessionRepository(c.DB)
+	c.ClassificationJobRepo = postgres.NewClassificationJobRepository(c.DB)
+	c.ReviewRepo = postgres.NewClassificationReviewRepository(c.DB)
+	c.ShadowRepo = postgres.NewShadowClassificationRepository(c.DB)
+	c.BinRuleRepo = postgres.NewBinRuleRepository(c.DB)
+
+	// Bins come from the active rule set; without one the AI service's bins are used
+	c.BinMappingService = services.NewBinMappingService(c.BinRuleRepo)
+	if err := c.BinMappingService.Reload(context.Background()); err != nil {
+		return err
+	}
 
-	// Initialize service with repository, storage adapter, and AI adapter
-	c.TrashService = services.NewTrashService(trashRepo, c.StorageAdapter, c.AIAdapter)
+	c.ShadowService = services.NewShadowService(c.ShadowRepo, c.ShadowAIAdapter, services.ShadowServiceConfig{
+		SampleRate:  c.Config.AI.ShadowSampleRate,
+		Concurrency: c.Config.AI.ShadowConcurrency,
+		Timeout:     time.Duration(c.Config.AI.ShadowTimeout) * time.Second,
+	})
+
+	c.ClassificationService = services.NewClassificationService(c.TrashRepo, c.ClassificationJobRepo, c.StorageAdapter, c.AIAdapter, c.ShadowService, c.BinMappingService, services.ClassificationServiceConfig{
+		PrivateBucket: c.Config.Storage.Private,
+		SignedURLTTL:  c.Config.Storage.SignedURLTTL,
+		MaxAttempts:   c.Config.AI.MaxAttempts,
+		RetryBackoff:  c.Config.AI.RetryBackoff,
+		JobLease:      c.Config.AI.JobLease,
+
+		SendImageData: c.aiNeedsImageData(),
+
+		ReviewConfidenceThreshold: c.Config.Review.ConfidenceThreshold,
+		ReviewNoObject:            c.Config.Review.NoObject,
+	})
+
+	// Initialize service with repository, storage adapter, and classification service
+	c.TrashService = services.NewTrashService(c.TrashRepo, c.UploadRepo, c.ReviewRepo, c.StorageAdapter, c.ClassificationService, services.TrashServiceConfig{
+		UploadExpiry:           time.Duration(c.Config.Storage.PresignedExpiry) * time.Second,
+		MaxUploadBytes:         c.Config.Storage.MaxUploadBytes,
+		AllowedContentTypes:    c.Config.Storage.AllowedContentTypes,
+		PrivateBucket:          c.Config.Storage.Private,
+		SignedURLTTL:           c.Config.Storage.SignedURLTTL,
+		VariantsEnabled:        c.Config.Storage.VariantsEnabled,
+		ThumbnailSize:          c.Config.Storage.ThumbnailSize,
+		MediumSize:             c.Config.Storage.MediumSize,
+		StripMetadata:          c.Config.Storage.StripMetadata,
+		LocationMismatchMeters: c.Config.Storage.LocationMismatchMeters,
+		DedupEnabled:           c.Config.Storage.DedupEnabled,
+		DedupWindow:            c.Config.Storage.DedupWindow,
+		DedupPHashThreshold:    c.Config.Storage.DedupPHashThreshold,
+		AsyncClassification:    c.Config.AI.Mode == "async",
+	})
+
+	c.StorageGCService = services.NewStorageGCService(c.TrashRepo, c.UploadRepo, c.StorageAdapter)
+	c.DatasetExportService = services.NewDatasetExportService(c.TrashRepo, c.StorageAdapter)
 
 	log.Println("✓ Services initialized")
 	return nil
 }
 
+// StartBackgroundJobs launches periodic jobs enabled in the configuration
+func (c *Container) StartBackgroundJobs() {
+	ctx, cancel := context.WithCancel(context.Background())
+	c.stopJobs = cancel
+
+	if c.Config.StorageGC.Enabled {
+		gcOpts := dto.StorageGCOptions{
+			GracePeriod: c.Config.StorageGC.GracePeriod,
+			Mode:        c.Config.StorageGC.Mode,
+			DryRun:      c.Config.StorageGC.DryRun,
+		}
+		jobs.RunPeriodically(ctx, "GC", c.Config.StorageGC.Interval, func(ctx context.Context) error {
+			_, err := c.StorageGCService.CollectOrphans(ctx, gcOpts)
+			return err
+		})
+		log.Printf("✓ Storage GC job started (Interval: %s, Grace: %s, Mode: %s)",
+			c.Config.StorageGC.Interval, c.Config.StorageGC.GracePeriod, c.Config.StorageGC.Mode)
+	}
+
+	// Probe every AI replica so failed ones rejoin as soon as they recover; the
+	// router logs state changes itself
+	jobs.RunPeriodically(ctx, "AI Health", c.Config.AI.HealthCheckInterval, func(ctx context.Context) error {
+		c.AIRouter.Health(ctx)
+		return nil
+	})
+	log.Printf("✓ AI health check job started (Interval: %s)", c.Config.AI.HealthCheckInterval)
+
+	// Pick up bin rules changed through another instance
+	jobs.RunPeriodically(ctx, "Bins", c.Config.BinRules.RefreshInterval, c.BinMappingService.Reload)
+	log.Printf("✓ Bin rules refresh job started (Interval: %s)", c.Config.BinRules.RefreshInterval)
+
+	if c.Config.AI.Mode == "async" {
+		jobs.RunWorkers(ctx, "AI", c.Config.AI.Workers, c.Config.AI.PollInterval, c.ClassificationService.ProcessNextJob)
+		log.Printf("✓ Classification workers started (Workers: %d, Max attempts: %d)", c.Config.AI.Workers, c.Config.AI.MaxAttempts)
+	}
+
+	if c.MirroredStorage != nil && c.Config.StorageMirror.RepairInterval > 0 {
+		jobs.RunPeriodically(ctx, "Mirror", c.Config.StorageMirror.RepairInterval, func(ctx context.Context) error {
+			report, err := c.MirroredStorage.Repair(ctx, "")
+			if report != nil {
+				log.Printf("[Mirror] Repair scanned %d objects: repaired %v, %d failed", report.Scanned, report.Repaired, report.Failed)
+			}
+			return err
+		})
+		log.Printf("✓ Storage mirror repair job started (Interval: %s)", c.Config.StorageMirror.RepairInterval)
+	}
+}
+
 // Cleanup closes all connections and releases resources
 func (c *Container) Cleanup() error {
 	log.Println("Starting cleanup...")
 
+	// Stop background jobs
+	if c.stopJobs != nil {
+		c.stopJobs()
+	}
+
+	// Close storage adapter if it holds open connections
+	if closer, ok := c.StorageAdapter.(io.Closer); ok {
+		if err := closer.Close(); err != nil {
+			log.Printf("Warning: Failed to close storage adapter: %v", err)
+		} else {
+			log.Println("✓ Storage adapter closed")
+		}
+	}
+
 	// Close database connection
 	if c.DB != nil {
 		sqlDB, err := c.DB.DB()
@@ -188,4 +453,35 @@ func (c *Container) GetConfig() *config.Config {
 // GetTrashService returns the trash service
 func (c *Container) GetTrashService() domainServices.TrashService {
 	return c.TrashService
-}
\ No newline at end of file
+}
+
+// GetLocalFileStore returns the local file store, or nil if another provider is used
+func (c *Container) GetLocalFileStore() ports.LocalFileStore {
+	return c.LocalFileStore
+}
+
+// GetAICircuit returns the circuit breaker state reporter of the AI adapter, or nil if it has none
+func (c *Container) GetAICircuit() ports.CircuitReporter {
+	reporter, _ := c.AIAdapter.(ports.CircuitReporter)
+	return reporter
+}
+
+// GetStorageGCService returns the orphaned image collector
+func (c *Container) GetStorageGCService() domainServices.StorageGCService {
+	return c.StorageGCService
+}
+
+// GetDatasetExportService returns the training dataset exporter
+func (c *Container) GetDatasetExportService() domainServices.DatasetExportService {
+	return c.DatasetExportService
+}
+
+// GetBinMappingService returns the bin rules service
+func (c *Container) GetBinMappingService() domainServices.BinMappingService {
+	return c.BinMappingService
+}
+
+// GetShadowService returns the shadow classification service
+func (c *Container) GetShadowService() domainServices.ShadowService {
+	return c.ShadowService
+}
@@ -0,0 +1,269 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// Metadata holds the EXIF fields kept from an uploaded photo
+type Metadata struct {
+	CapturedAt  *time.Time
+	Orientation int // EXIF orientation 1-8, 0 if absent
+	Latitude    *float64
+	Longitude   *float64
+}
+
+// ErrMalformedImage is returned when an image container cannot be parsed
+var ErrMalformedImage = errors.New("malformed image")
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// ExtractMetadata reads EXIF from a JPEG, PNG or WebP image.
+// Images without EXIF return empty metadata and no error.
+func ExtractMetadata(data []byte, contentType string) (Metadata, error) {
+	var meta Metadata
+
+	raw := data
+	if contentType != "image/jpeg" {
+		var err error
+		if raw, err = rawEXIF(data, contentType); err != nil || raw == nil {
+			return meta, err
+		}
+	}
+
+	x, err := exif.Decode(bytes.NewReader(raw))
+	if err != nil && (x == nil || exif.IsCriticalError(err)) {
+		// goexif reports a missing APP1 segment as an error too
+		if contentType == "image/jpeg" && !hasJPEGSegment(data, 0xE1, "Exif\x00\x00") {
+			return meta, nil
+		}
+		return meta, fmt.Errorf("failed to parse EXIF: %w", err)
+	}
+
+	if t, err := x.DateTime(); err == nil {
+		meta.CapturedAt = &t
+	}
+	if tag, err := x.Get(exif.Orientation); err == nil {
+		if v, err := tag.Int(0); err == nil && v >= 1 && v <= 8 {
+			meta.Orientation = v
+		}
+	}
+	if lat, lng, err := x.LatLong(); err == nil && (lat != 0 || lng != 0) {
+		meta.Latitude = &lat
+		meta.Longitude = &lng
+	}
+	return meta, nil
+}
+
+// StripMetadata removes EXIF, XMP and text metadata from an image. JPEGs with a
+// non-default orientation are re-encoded upright so they still display correctly;
+// everything else is stripped losslessly. Data without metadata is returned as is.
+func StripMetadata(data []byte, contentType string, orientation int) ([]byte, error) {
+	switch contentType {
+	case "image/jpeg":
+		if orientation > 1 {
+			img, err := Decode(bytes.NewReader(data))
+			if err != nil {
+				return nil, err
+			}
+			// The JPEG encoder writes no metadata segments
+			return EncodeJPEG(ApplyOrientation(img, orientation), strippedQuality)
+		}
+		return stripJPEG(data)
+	case "image/png":
+		return stripPNG(data)
+	case "image/webp":
+		return stripWebP(data)
+	default:
+		return nil, fmt.Errorf("unsupported content type %s", contentType)
+	}
+}
+
+// strippedQuality is used when a JPEG has to be re-encoded to apply its orientation
+const strippedQuality = 92
+
+// stripJPEG drops APP1 (EXIF/XMP) and APP13 (IPTC) segments, keeping the
+// entropy-coded data untouched
+func stripJPEG(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, ErrMalformedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:2]...)
+	stripped := false
+	for pos := 2; ; {
+		if pos+4 > len(data) || data[pos] != 0xFF {
+			return nil, ErrMalformedImage
+		}
+		marker := data[pos+1]
+		// Fill bytes may precede a marker
+		if marker == 0xFF {
+			pos++
+			continue
+		}
+		// Start of scan: the rest of the file is image data
+		if marker == 0xDA {
+			out = append(out, data[pos:]...)
+			break
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if end > len(data) {
+			return nil, ErrMalformedImage
+		}
+		if marker == 0xE1 || marker == 0xED {
+			stripped = true
+		} else {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+
+	if !stripped {
+		return data, nil
+	}
+	return out, nil
+}
+
+// stripPNG drops eXIf and text chunks (tEXt, zTXt, iTXt, which also carry XMP)
+func stripPNG(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, ErrMalformedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+	stripped := false
+	err := walkPNGChunks(data, func(chunkType string, chunk []byte) {
+		switch chunkType {
+		case "eXIf", "tEXt", "zTXt", "iTXt":
+			stripped = true
+		default:
+			out = append(out, chunk...)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !stripped {
+		return data, nil
+	}
+	return out, nil
+}
+
+// stripWebP drops EXIF and XMP chunks and clears their VP8X feature flags
+func stripWebP(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, ErrMalformedImage
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, data[:12]...)
+	stripped := false
+	err := walkWebPChunks(data, func(chunkType string, chunk []byte) {
+		switch chunkType {
+		case "EXIF", "XMP ":
+			stripped = true
+		case "VP8X":
+			start := len(out)
+			out = append(out, chunk...)
+			if len(chunk) > 8 {
+				// Flags byte: bit 3 is EXIF, bit 2 is XMP
+				out[start+8] &^= 0x08 | 0x04
+			}
+		default:
+			out = append(out, chunk...)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !stripped {
+		return data, nil
+	}
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
+
+// rawEXIF returns the EXIF payload embedded in a PNG or WebP, or nil if there is none
+func rawEXIF(data []byte, contentType string) ([]byte, error) {
+	var raw []byte
+	switch contentType {
+	case "image/png":
+		if !bytes.HasPrefix(data, pngSignature) {
+			return nil, ErrMalformedImage
+		}
+		err := walkPNGChunks(data, func(chunkType string, chunk []byte) {
+			if chunkType == "eXIf" && raw == nil {
+				// Skip length and type, drop the trailing CRC
+				raw = chunk[8 : len(chunk)-4]
+			}
+		})
+		return raw, err
+	case "image/webp":
+		if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+			return nil, ErrMalformedImage
+		}
+		err := walkWebPChunks(data, func(chunkType string, chunk []byte) {
+			if chunkType == "EXIF" && raw == nil {
+				// Skip FourCC and size, drop the padding byte
+				raw = chunk[8 : 8+binary.LittleEndian.Uint32(chunk[4:])]
+			}
+		})
+		return raw, err
+	default:
+		return nil, nil
+	}
+}
+
+// walkPNGChunks calls fn with the type and full bytes (length, type, data, CRC) of each chunk
+func walkPNGChunks(data []byte, fn func(chunkType string, chunk []byte)) error {
+	for pos := len(pngSignature); pos < len(data); {
+		if pos+12 > len(data) {
+			return ErrMalformedImage
+		}
+		end := pos + 12 + int(binary.BigEndian.Uint32(data[pos:]))
+		if end > len(data) || end < pos {
+			return ErrMalformedImage
+		}
+		fn(string(data[pos+4:pos+8]), data[pos:end])
+		pos = end
+	}
+	return nil
+}
+
+// walkWebPChunks calls fn with the FourCC and full bytes (header, data, padding) of each chunk
+func walkWebPChunks(data []byte, fn func(chunkType string, chunk []byte)) error {
+	for pos := 12; pos < len(data); {
+		if pos+8 > len(data) {
+			return ErrMalformedImage
+		}
+		size := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + size + size%2
+		if end > len(data) || end < pos {
+			return ErrMalformedImage
+		}
+		fn(string(data[pos:pos+4]), data[pos:end])
+		pos = end
+	}
+	return nil
+}
+
+// hasJPEGSegment reports whether a JPEG contains an APPn segment starting with prefix
+func hasJPEGSegment(data []byte, marker byte, prefix string) bool {
+	for pos := 2; pos+4 <= len(data) && data[pos] == 0xFF && data[pos+1] != 0xDA; {
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:]))
+		if data[pos+1] == marker && bytes.HasPrefix(data[pos+4:min(end, len(data))], []byte(prefix)) {
+			return true
+		}
+		pos = end
+	}
+	return false
+}
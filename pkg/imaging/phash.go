@@ -0,0 +1,38 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"math/bits"
+
+	"golang.org/x/image/draw"
+)
+
+// DifferenceHash computes a 64-bit perceptual hash (dHash) of img.
+// Visually similar images (re-encoded, slightly resized) produce hashes
+// with a small Hamming distance.
+func DifferenceHash(img image.Image) uint64 {
+	// Shrink to 9x8 grayscale so each row yields 8 left/right comparisons
+	small := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.ApproxBiLinear.Scale(small, small.Bounds(), img, img.Bounds(), draw.Src, nil)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			hash <<= 1
+			if grayAt(small, x, y) > grayAt(small, x+1, y) {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// HammingDistance returns the number of differing bits between two hashes
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+func grayAt(img *image.Gray, x, y int) uint8 {
+	return img.At(x, y).(color.Gray).Y
+}
@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunPeriodically runs fn immediately and then every interval until ctx is cancelled.
+// Errors are logged and do not stop the schedule.
+func RunPeriodically(ctx context.Context, name string, interval time.Duration, fn func(context.Context) error) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			if err := fn(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("[%s] Job failed: %v", name, err)
+			}
+
+			select {
+			case <-ctx.Done():
+				log.Printf("[%s] Job stopped", name)
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
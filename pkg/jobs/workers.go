@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// RunWorkers starts n goroutines that call fn until ctx is cancelled. fn reports
+// whether it did any work: busy workers call it again right away, idle or failing
+// ones wait pollInterval first.
+func RunWorkers(ctx context.Context, name string, n int, pollInterval time.Duration, fn func(context.Context) (bool, error)) {
+	for i := 0; i < n; i++ {
+		go func() {
+			for {
+				worked, err := fn(ctx)
+				if err != nil && ctx.Err() == nil {
+					log.Printf("[%s] Job failed: %v", name, err)
+				}
+				if worked && err == nil {
+					if ctx.Err() != nil {
+						return
+					}
+					continue
+				}
+
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(pollInterval):
+				}
+			}
+		}()
+	}
+}
@@ -0,0 +1,17 @@
+package utils
+
+import "time"
+
+// ParseDate parses a date (2006-01-02) or RFC 3339 timestamp, returning nil for an empty string
+func ParseDate(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		if t, err = time.Parse(time.DateOnly, value); err != nil {
+			return nil, err
+		}
+	}
+	return &t, nil
+}